@@ -0,0 +1,144 @@
+// Package apperror provide typed errors which are sent to clients with matching HTTP status code
+package apperror
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Code is stable, machine readable kind of error
+type Code string
+
+// Codes of errors
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeMethodNotAllowed Code = "method_not_allowed"
+	CodeConflict         Code = "conflict"
+	CodeValidationFailed Code = "validation_failed"
+	CodeInternal         Code = "internal"
+	CodeUnavailable      Code = "unavailable"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:       http.StatusBadRequest,
+	CodeUnauthorized:     http.StatusUnauthorized,
+	CodeForbidden:        http.StatusForbidden,
+	CodeNotFound:         http.StatusNotFound,
+	CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+	CodeConflict:         http.StatusConflict,
+	CodeValidationFailed: http.StatusUnprocessableEntity,
+	CodeInternal:         http.StatusInternalServerError,
+	CodeUnavailable:      http.StatusServiceUnavailable,
+}
+
+// InternalMessage is sent to client instead of message of internal error
+const InternalMessage = "Internal server error"
+
+// UnavailableMessage is sent to client instead of message of error caused by lost connection to database
+const UnavailableMessage = "Service is temporarily unavailable"
+
+// Error is error with code, message safe to show to client and optional details
+type Error struct {
+	Code    Code
+	Message string
+	Details interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Status return HTTP status code matching error code
+func (e *Error) Status() int {
+	status, ok := statuses[e.Code]
+	if !ok {
+		return http.StatusInternalServerError
+	}
+	return status
+}
+
+// WithDetails return copy of error with details
+func (e *Error) WithDetails(details interface{}) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Details: details}
+}
+
+// New create error with code and message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// BadRequest create error of request which can not be read
+func BadRequest(message string) *Error {
+	return New(CodeBadRequest, message)
+}
+
+// Unauthorized create error of request without valid credentials
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, message)
+}
+
+// Forbidden create error of request which is not allowed for user
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+// NotFound create error of not existing resource
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+// MethodNotAllowed create error of request with HTTP method which is not supported by resource
+func MethodNotAllowed(message string) *Error {
+	return New(CodeMethodNotAllowed, message)
+}
+
+// Conflict create error of resource which collides with existing one
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+// ValidationFailed create error of request with invalid data
+func ValidationFailed(message string) *Error {
+	return New(CodeValidationFailed, message)
+}
+
+// Unavailable create error of request which can not be handled now, because dependency is down
+func Unavailable(message string) *Error {
+	return New(CodeUnavailable, message)
+}
+
+// From return typed error found in err chain. Lost connection to database is unavailable error and any other
+// error is internal one, both with hidden message.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if isConnectionError(err) {
+		return Unavailable(UnavailableMessage)
+	}
+	return New(CodeInternal, InternalMessage)
+}
+
+// isConnectionError tell if err means that database can not be reached, like refused or broken connection
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Response is body of error response
+type Response struct {
+	Code      Code        `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details"`
+	RequestID string      `json:"requestId"`
+}
@@ -0,0 +1,70 @@
+package apperror
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	cases := map[*Error]int{
+		BadRequest("a"):       http.StatusBadRequest,
+		Unauthorized("a"):     http.StatusUnauthorized,
+		Forbidden("a"):        http.StatusForbidden,
+		NotFound("a"):         http.StatusNotFound,
+		MethodNotAllowed("a"): http.StatusMethodNotAllowed,
+		Conflict("a"):         http.StatusConflict,
+		ValidationFailed("a"): http.StatusUnprocessableEntity,
+		Unavailable("a"):      http.StatusServiceUnavailable,
+		New("unknown", "a"):   http.StatusInternalServerError,
+	}
+
+	for err, status := range cases {
+		if err.Status() != status {
+			t.Errorf("Wrong status of %s, expected %d, got %d", err.Code, status, err.Status())
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+	notFound := NotFound("Movie not found")
+
+	if From(notFound) != notFound {
+		t.Error("Typed error was not returned")
+	}
+
+	if From(fmt.Errorf("Can not update: %w", notFound)) != notFound {
+		t.Error("Wrapped typed error was not returned")
+	}
+
+	internal := From(fmt.Errorf("Error 1045: Access denied for user 'movie_user'"))
+	if internal.Code != CodeInternal || internal.Message != InternalMessage {
+		t.Errorf("Wrong internal error, got %v", internal)
+	}
+}
+
+func TestFromConnectionError(t *testing.T) {
+	errs := []error{
+		&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+		fmt.Errorf("Can not read movie: %w", driver.ErrBadConn),
+	}
+
+	for _, err := range errs {
+		unavailable := From(err)
+		if unavailable.Code != CodeUnavailable || unavailable.Message != UnavailableMessage {
+			t.Errorf("Wrong error of %v, got %v", err, unavailable)
+		}
+	}
+}
+
+func TestWithDetails(t *testing.T) {
+	err := ValidationFailed("Invalid payload")
+	detailed := err.WithDetails([]string{"name"})
+
+	if err.Details != nil || detailed.Details == nil || detailed.Code != err.Code {
+		t.Errorf("Wrong details, got %v and %v", err, detailed)
+	}
+}
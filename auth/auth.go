@@ -0,0 +1,149 @@
+// Package auth provide authentication of requests and access to authenticated principal in request context
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/Mowinski/LastWatchedBackend/utils"
+)
+
+// ErrInvalidCredentials is returned when username does not exist or password is wrong
+var ErrInvalidCredentials = apperror.Unauthorized("Invalid username or password")
+
+// ErrMissingCredentials is returned when request has neither bearer token nor API key
+var ErrMissingCredentials = apperror.Unauthorized("Authentication required")
+
+// ErrInvalidAPIKey is returned when API key is not configured or its user does not exist
+var ErrInvalidAPIKey = apperror.Unauthorized("Invalid API key")
+
+// Authentication methods stored in Principal
+const (
+	MethodToken  = "token"
+	MethodAPIKey = "apikey"
+)
+
+// Principal describe who sent request and how it was authenticated
+type Principal struct {
+	User   models.User
+	Method string
+}
+
+// APIKey is static key which allows scripts to act as selected user
+type APIKey struct {
+	Name     string
+	Key      string
+	Username string
+}
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal return copy of context which carries authenticated principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext return principal stored in context by WithPrincipal
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(Principal)
+	return principal, ok
+}
+
+// UserFromContext return user of principal stored in context
+func UserFromContext(ctx context.Context) (models.User, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	return principal.User, ok
+}
+
+// CheckCredentials return user when username and password match stored account
+func CheckCredentials(users database.UserRepository, username string, password string) (models.User, error) {
+	user, err := users.RetrieveUserByName(username)
+	if err == database.ErrUserNotFound {
+		return user, ErrInvalidCredentials
+	}
+	if err != nil {
+		return user, err
+	}
+
+	if !CheckPassword(user.PasswordHash, password) {
+		return models.User{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// Authenticator recognize principal of request by bearer token (Authorization: Bearer <token>)
+// or static API key (X-API-Key: <key>)
+type Authenticator struct {
+	Users   database.UserRepository
+	Tokens  *JWT
+	APIKeys []APIKey
+}
+
+// Authenticate return principal of request
+func (a Authenticator) Authenticate(r *http.Request) (principal Principal, err error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return a.authenticateAPIKey(key)
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return principal, ErrMissingCredentials
+	}
+
+	tokenUser, err := a.Tokens.Verify(strings.TrimPrefix(authorization, "Bearer "))
+	if err != nil {
+		return principal, err
+	}
+
+	// user is read again, so role changes apply to already issued tokens
+	user, err := a.Users.RetrieveUserByName(tokenUser.Username)
+	if err == database.ErrUserNotFound || (err == nil && user.ID != tokenUser.ID) {
+		return principal, ErrInvalidToken
+	}
+	if err != nil {
+		return principal, err
+	}
+	return Principal{User: user, Method: MethodToken}, nil
+}
+
+func (a Authenticator) authenticateAPIKey(key string) (principal Principal, err error) {
+	var matched *APIKey
+	for i := range a.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(a.APIKeys[i].Key), []byte(key)) == 1 {
+			matched = &a.APIKeys[i]
+		}
+	}
+	if matched == nil {
+		return principal, ErrInvalidAPIKey
+	}
+
+	user, err := a.Users.RetrieveUserByName(matched.Username)
+	if err == database.ErrUserNotFound {
+		return principal, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return principal, err
+	}
+	return Principal{User: user, Method: MethodAPIKey}, nil
+}
+
+// Middleware allow only authenticated requests, principal is available to next handler through PrincipalFromContext
+func (a Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="LastWatched"`)
+			utils.RespondWithError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/logger"
+)
+
+func setupUsers(t *testing.T) *database.MemoryRepository {
+	repository := database.NewMemoryRepository()
+
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = repository.CreateUser("john", hash)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	return repository
+}
+
+func TestCheckCredentials(t *testing.T) {
+	users := setupUsers(t)
+
+	user, err := CheckCredentials(users, "john", "secret")
+	if err != nil || user.Username != "john" {
+		t.Errorf("Expected user 'john', got %v, %v", user, err)
+	}
+
+	_, err = CheckCredentials(users, "john", "wrong")
+	if err != ErrInvalidCredentials {
+		t.Errorf("Wrong error for bad password, expected '%s', got '%v'", ErrInvalidCredentials, err)
+	}
+
+	_, err = CheckCredentials(users, "jane", "secret")
+	if err != ErrInvalidCredentials {
+		t.Errorf("Wrong error for unknown user, expected '%s', got '%v'", ErrInvalidCredentials, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	users := setupUsers(t)
+	tokens := NewJWT("secret", time.Hour)
+	authenticator := Authenticator{
+		Users:  users,
+		Tokens: tokens,
+		APIKeys: []APIKey{
+			{Name: "backup", Key: "john-key", Username: "john"},
+			{Name: "removed", Key: "ghost-key", Username: "ghost"},
+		},
+	}
+
+	john, _ := users.RetrieveUserByName("john")
+	token, _, _ := tokens.Sign(john)
+	otherToken, _, _ := NewJWT("other secret", time.Hour).Sign(john)
+
+	var principal Principal
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+	}))
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		status int
+		method string
+	}{
+		{"bearer token", "Authorization", "Bearer " + token, http.StatusOK, MethodToken},
+		{"token signed with other secret", "Authorization", "Bearer " + otherToken, http.StatusUnauthorized, ""},
+		{"basic credentials", "Authorization", "Basic am9objpzZWNyZXQ=", http.StatusUnauthorized, ""},
+		{"api key", "X-API-Key", "john-key", http.StatusOK, MethodAPIKey},
+		{"unknown api key", "X-API-Key", "jane-key", http.StatusUnauthorized, ""},
+		{"api key of not existing user", "X-API-Key", "ghost-key", http.StatusUnauthorized, ""},
+		{"no credentials", "", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, c := range cases {
+		principal = Principal{}
+		req, _ := http.NewRequest("GET", "/movies", nil)
+		if c.header != "" {
+			req.Header.Set(c.header, c.value)
+		}
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != c.status {
+			t.Errorf("Wrong status code for %s, expected %d, got %d", c.name, c.status, rr.Code)
+		}
+		if c.status == http.StatusOK && (principal.User.Username != "john" || principal.Method != c.method) {
+			t.Errorf("Wrong principal in context for %s, got %v", c.name, principal)
+		}
+		if c.status == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("WWW-Authenticate header is missing for %s", c.name)
+		}
+	}
+}
+
+func TestUserFromEmptyContext(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/movies", nil)
+
+	_, ok := UserFromContext(req.Context())
+	if ok {
+		t.Error("Expected no user in empty context")
+	}
+}
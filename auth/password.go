@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	passwordHashPrefix = "pbkdf2-sha256"
+	passwordIterations = 100000
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+// HashPassword return salted PBKDF2 hash of password in form pbkdf2-sha256$iterations$salt$key
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"%s$%d$%s$%s",
+		passwordHashPrefix,
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// CheckPassword return true when password match hash created by HashPassword
+func CheckPassword(hash string, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordHashPrefix {
+		return false
+	}
+
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !strings.HasPrefix(hash, "pbkdf2-sha256$") || strings.Contains(hash, "secret") {
+		t.Errorf("Wrong hash format, got %s", hash)
+	}
+
+	if !CheckPassword(hash, "secret") {
+		t.Error("Correct password was rejected")
+	}
+
+	if CheckPassword(hash, "Secret") {
+		t.Error("Wrong password was accepted")
+	}
+}
+
+func TestHashPasswordUniqueSalt(t *testing.T) {
+	first, _ := HashPassword("secret")
+	second, _ := HashPassword("secret")
+
+	if first == second {
+		t.Error("Two hashes of the same password are equal, salt is not used")
+	}
+}
+
+func TestCheckPasswordMalformedHash(t *testing.T) {
+	for _, hash := range []string{"", "secret", "md5$1$c2FsdA$a2V5", "pbkdf2-sha256$x$c2FsdA$a2V5", "pbkdf2-sha256$1$!$a2V5"} {
+		if CheckPassword(hash, "secret") {
+			t.Errorf("Malformed hash %q was accepted", hash)
+		}
+	}
+}
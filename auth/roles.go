@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/Mowinski/LastWatchedBackend/utils"
+)
+
+// ErrPermissionDenied is returned when role of principal does not allow to call route
+var ErrPermissionDenied = apperror.Forbidden("Permission denied")
+
+// Permission is required from principal to call route
+type Permission string
+
+// Permissions of routes, PermissionPublic routes are available without authentication
+const (
+	PermissionPublic Permission = "public"
+	PermissionRead   Permission = "read"
+	PermissionWrite  Permission = "write"
+	PermissionAdmin  Permission = "admin"
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleViewer: {PermissionRead},
+	models.RoleEditor: {PermissionRead, PermissionWrite},
+	models.RoleAdmin:  {PermissionRead, PermissionWrite, PermissionAdmin},
+}
+
+// ValidRole return true when role is known
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission return true when role grants permission
+func HasPermission(role string, permission Permission) bool {
+	if permission == PermissionPublic {
+		return true
+	}
+
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission allow request only when role of principal from context grants permission,
+// it has to be placed after Authenticator.Middleware
+func RequirePermission(permission Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := UserFromContext(r.Context())
+		if !HasPermission(user.Role, permission) {
+			utils.RespondWithError(w, r, ErrPermissionDenied)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
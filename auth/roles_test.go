@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+func TestHasPermission(t *testing.T) {
+	cases := []struct {
+		role       string
+		permission Permission
+		allowed    bool
+	}{
+		{models.RoleViewer, PermissionRead, true},
+		{models.RoleViewer, PermissionWrite, false},
+		{models.RoleViewer, PermissionAdmin, false},
+		{models.RoleEditor, PermissionWrite, true},
+		{models.RoleEditor, PermissionAdmin, false},
+		{models.RoleAdmin, PermissionAdmin, true},
+		{"", PermissionRead, false},
+		{"", PermissionPublic, true},
+		{"root", PermissionRead, false},
+	}
+
+	for _, c := range cases {
+		if HasPermission(c.role, c.permission) != c.allowed {
+			t.Errorf("HasPermission(%q, %q) expected %v", c.role, c.permission, c.allowed)
+		}
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	if !ValidRole(models.RoleEditor) || ValidRole("root") || ValidRole("") {
+		t.Error("Wrong role validation")
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	called := false
+	handler := RequirePermission(PermissionWrite, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	for role, status := range map[string]int{models.RoleViewer: http.StatusForbidden, models.RoleEditor: http.StatusOK, models.RoleAdmin: http.StatusOK} {
+		called = false
+		req, _ := http.NewRequest("POST", "/movie", nil)
+		req = req.WithContext(WithPrincipal(req.Context(), Principal{User: models.User{ID: 1, Role: role}}))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != status || called != (status == http.StatusOK) {
+			t.Errorf("Wrong result for role %s, expected %d, got %d (handler called: %v)", role, status, rr.Code, called)
+		}
+	}
+}
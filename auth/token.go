@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// ErrInvalidToken is returned when token is malformed or its signature does not match
+var ErrInvalidToken = apperror.Unauthorized("Invalid token")
+
+// ErrTokenExpired is returned when token lifetime is over
+var ErrTokenExpired = apperror.Unauthorized("Token expired")
+
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWT sign and verify HMAC-SHA256 JSON Web Tokens which identify user
+type JWT struct {
+	secret   []byte
+	lifetime time.Duration
+	now      func() time.Time
+}
+
+// NewJWT create JWT signing tokens with secret, tokens are valid for lifetime since they were signed
+func NewJWT(secret string, lifetime time.Duration) *JWT {
+	return &JWT{secret: []byte(secret), lifetime: lifetime, now: time.Now}
+}
+
+// Sign return token of user and time when it expires
+func (j *JWT) Sign(user models.User) (token string, expiresAt time.Time, err error) {
+	issuedAt := j.now()
+	expiresAt = issuedAt.Add(j.lifetime)
+
+	claims, err := json.Marshal(tokenClaims{
+		Subject:   strconv.FormatInt(user.ID, 10),
+		Name:      user.Username,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", expiresAt, err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return unsigned + "." + j.signature(unsigned), expiresAt, nil
+}
+
+// Verify check token signature and expiration time and return user identified by token
+func (j *JWT) Verify(token string) (user models.User, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return user, ErrInvalidToken
+	}
+
+	expected := j.signature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return user, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return user, ErrInvalidToken
+	}
+
+	var claims tokenClaims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return user, ErrInvalidToken
+	}
+
+	if j.now().Unix() >= claims.ExpiresAt {
+		return user, ErrTokenExpired
+	}
+
+	user.ID, err = strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return models.User{}, ErrInvalidToken
+	}
+	user.Username = claims.Name
+	return user, nil
+}
+
+func (j *JWT) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+func TestSignAndVerifyToken(t *testing.T) {
+	tokens := NewJWT("secret", time.Hour)
+
+	token, expiresAt, err := tokens.Sign(models.User{ID: 7, Username: "john"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if strings.Count(token, ".") != 2 {
+		t.Errorf("Token is not JWT, got %s", token)
+	}
+
+	if expiresAt.Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Wrong expiration time, got %s", expiresAt)
+	}
+
+	user, err := tokens.Verify(token)
+	if err != nil || user.ID != 7 || user.Username != "john" {
+		t.Errorf("Expected user 7 'john', got %v, %v", user, err)
+	}
+}
+
+func TestVerifyExpiredToken(t *testing.T) {
+	tokens := NewJWT("secret", time.Hour)
+	tokens.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	token, _, _ := tokens.Sign(models.User{ID: 7, Username: "john"})
+
+	tokens.now = time.Now
+	_, err := tokens.Verify(token)
+	if err != ErrTokenExpired {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrTokenExpired, err)
+	}
+}
+
+func TestVerifyTamperedToken(t *testing.T) {
+	tokens := NewJWT("secret", time.Hour)
+	token, _, _ := tokens.Sign(models.User{ID: 7, Username: "john"})
+	parts := strings.Split(token, ".")
+
+	forgedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","name":"admin","iat":0,"exp":9999999999}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	for _, forged := range []string{
+		"",
+		"not a token",
+		parts[0] + "." + forgedClaims + "." + parts[2],
+		noneHeader + "." + parts[1] + ".",
+		parts[0] + "." + parts[1],
+	} {
+		_, err := tokens.Verify(forged)
+		if err != ErrInvalidToken {
+			t.Errorf("Wrong error for %q, expected '%s', got '%v'", forged, ErrInvalidToken, err)
+		}
+	}
+}
@@ -1,30 +1,47 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-
-	"github.com/Mowinski/LastWatchedBackend/logger"
+	"errors"
 )
 
+// ErrNotConnected is returned when database connection was not opened
+var ErrNotConnected = errors.New("Database connection is not opened")
+
 var dbConn *sql.DB
 
 // ConnectWithDatabase connect to selected mysql and associate it with global variable DBConn
 func ConnectWithDatabase(dns string) (err error) {
-	dbConn, err = sql.Open("mysql", dns)
+	return ConnectWithDriver("mysql", dns)
+}
+
+// ConnectWithDriver connect to database using selected driver and associate it with global variable DBConn
+func ConnectWithDriver(driverName string, dns string) (err error) {
+	dbConn, err = sql.Open(driverName, dns)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// GetDBConn return database object
-func GetDBConn() *sql.DB {
-	err := dbConn.Ping()
+// GetDBConn return database object, error is returned when database server does not respond to ping
+func GetDBConn() (*sql.DB, error) {
+	return GetDBConnContext(context.Background())
+}
+
+// GetDBConnContext return database object like GetDBConn, ping is canceled when ctx is done
+func GetDBConnContext(ctx context.Context) (*sql.DB, error) {
+	if dbConn == nil {
+		return nil, ErrNotConnected
+	}
+
+	err := dbConn.PingContext(ctx)
 	if err != nil {
-		logger.Logger.Fatal("Can not ping database server, error: ", err)
+		return nil, err
 	}
 
-	return dbConn
+	return dbConn, nil
 }
 
 // SetDBConn set database object
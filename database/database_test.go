@@ -27,8 +27,31 @@ func TestGetDBConnection(t *testing.T) {
 
 	SetDBConn(db)
 
-	if db != GetDBConn() {
-		t.Error("Set DB object does not match with getting")
+	conn, err := GetDBConn()
+	if err != nil || db != conn {
+		t.Errorf("Set DB object does not match with getting, error: %v", err)
+	}
+}
+
+func TestGetDBConnectionNotConnected(t *testing.T) {
+	SetDBConn(nil)
+
+	_, err := GetDBConn()
+	if err != ErrNotConnected {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrNotConnected, err)
+	}
+}
+
+func TestGetDBConnectionPingFailed(t *testing.T) {
+	err := ConnectWithDriver("sqlite", "/not/existing/directory/movies.db")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer SetDBConn(nil)
+
+	_, err = GetDBConn()
+	if err == nil {
+		t.Error("Expected error when database server does not respond")
 	}
 }
 
@@ -0,0 +1,675 @@
+package database
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// MemoryRepository is Repository which keeps users and their movies in memory, it is safe for concurrent use
+type MemoryRepository struct {
+	mutex         sync.RWMutex
+	lastMovieID   int64
+	lastEpisodeID int64
+	movies        map[int64]*memoryMovie
+	users         []models.User
+}
+
+type memoryMovie struct {
+	id        int64
+	userID    int64
+	name      string
+	url       string
+	tags      []string        // normalized by normalizeTags
+	deletedAt *time.Time      // set when movie is in trash
+	seasons   []*memorySeason // season number N is kept under index N-1
+}
+
+type memorySeason struct {
+	episodes []*memoryEpisode // episode number N is kept under index N-1
+}
+
+type memoryEpisode struct {
+	id      int64
+	title   string
+	airDate *models.Date
+	watched bool
+	date    *time.Time
+}
+
+// NewMemoryRepository create empty in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{movies: map[int64]*memoryMovie{}}
+}
+
+// memoryProgress describe watching of movie the same way as movieProgressTable does in SQL
+type memoryProgress struct {
+	movie       *memoryMovie
+	episodes    int
+	watched     int
+	lastWatched *time.Time
+}
+
+// RetrieveMovieItems return movies of user selected and sorted by query, ErrInvalidMovieListQuery is returned
+// for unknown status, sort column or order
+func (r *MemoryRepository) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	_, knownColumn := movieSortColumns[query.Sort]
+	_, knownOrder := sortOrders[query.Order]
+	_, knownStatus := movieStatusConditions[query.Status]
+	if !knownColumn || !knownOrder || (query.Status != "" && !knownStatus) {
+		return movies, ErrInvalidMovieListQuery
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var selected []memoryProgress
+	for _, movie := range r.sortedMovies() {
+		if movie.userID != userID || movie.deletedAt != nil {
+			continue
+		}
+		progress := newMemoryProgress(movie)
+		if progress.matches(query) {
+			selected = append(selected, progress)
+		}
+	}
+	sortMemoryProgress(selected, query.Sort, query.Order == "desc")
+
+	for _, progress := range selected {
+		if query.Skip > 0 {
+			query.Skip--
+			continue
+		}
+		if len(movies) >= query.Limit {
+			break
+		}
+		movie := progress.movie
+		movies = append(movies, models.MovieItem{ID: int(movie.id), Name: movie.name, URL: movie.url, Progress: progressPercent(progress.watched, progress.episodes)})
+	}
+	return movies, nil
+}
+
+func newMemoryProgress(movie *memoryMovie) (progress memoryProgress) {
+	progress.movie = movie
+	for _, season := range movie.seasons {
+		for _, episode := range season.episodes {
+			progress.episodes++
+			if !episode.watched {
+				continue
+			}
+			progress.watched++
+			if episode.date != nil && (progress.lastWatched == nil || episode.date.After(*progress.lastWatched)) {
+				progress.lastWatched = episode.date
+			}
+		}
+	}
+	return progress
+}
+
+// matches check if movie passes all filters of query
+func (p memoryProgress) matches(query models.MovieListQuery) bool {
+	if query.SearchString != "" && !likeMatch(strings.ToLower(query.SearchString), strings.ToLower(p.movie.name)) {
+		return false
+	}
+
+	switch query.Status {
+	case models.MovieStatusFinished:
+		if p.episodes == 0 || p.watched < p.episodes {
+			return false
+		}
+	case models.MovieStatusInProgress:
+		if p.watched == 0 || p.watched == p.episodes {
+			return false
+		}
+	case models.MovieStatusNotStarted:
+		if p.watched > 0 {
+			return false
+		}
+	}
+
+	if query.HasUnwatched != nil && *query.HasUnwatched != (p.watched < p.episodes) {
+		return false
+	}
+	if query.WatchedSince != nil && (p.lastWatched == nil || p.lastWatched.Before(*query.WatchedSince)) {
+		return false
+	}
+	if query.Tag != "" && !containsTag(p.movie.tags, normalizeTag(query.Tag)) {
+		return false
+	}
+	return true
+}
+
+// sortMemoryProgress sort movies by column (by creation when it is empty), ties are ordered by creation
+// in the same direction, movies without watched episode are always placed at the end when they are
+// sorted by last watched episode
+func sortMemoryProgress(movies []memoryProgress, column string, descending bool) {
+	sort.SliceStable(movies, func(i, j int) bool {
+		first, second := movies[i], movies[j]
+		if column == models.MovieSortLastWatched && (first.lastWatched == nil) != (second.lastWatched == nil) {
+			return second.lastWatched == nil
+		}
+		if descending {
+			first, second = second, first
+		}
+
+		switch column {
+		case models.MovieSortName:
+			if first.movie.name != second.movie.name {
+				return first.movie.name < second.movie.name
+			}
+		case models.MovieSortLastWatched:
+			if first.lastWatched != nil && !first.lastWatched.Equal(*second.lastWatched) {
+				return first.lastWatched.Before(*second.lastWatched)
+			}
+		case models.MovieSortProgress:
+			firstProgress, secondProgress := progressPercent(first.watched, first.episodes), progressPercent(second.watched, second.episodes)
+			if firstProgress != secondProgress {
+				return firstProgress < secondProgress
+			}
+		}
+		return first.movie.id < second.movie.id
+	})
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// RetrieveMovieDetail found movie details, for not existing movie or movie of other user ErrMovieNotFound is returned
+func (r *MemoryRepository) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.movie(userID, movieID) == nil {
+		return movie, ErrMovieNotFound
+	}
+	return r.movieDetail(userID, movieID), nil
+}
+
+// CreateMovie function create movie of user in memory
+func (r *MemoryRepository) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+	seasons := payload.SeasonsLayout()
+	err = validateSeasonsLayout(seasons)
+	if err != nil {
+		return movie, err
+	}
+
+	tags, err := normalizeTags(payload.Tags)
+	if err != nil {
+		return movie, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.nameTaken(userID, payload.MovieName, 0) {
+		return movie, ErrDuplicateMovieName
+	}
+
+	r.lastMovieID++
+	stored := &memoryMovie{id: r.lastMovieID, userID: userID, name: payload.MovieName, url: payload.URL, tags: tags}
+	for _, season := range seasons {
+		stored.seasons = append(stored.seasons, &memorySeason{})
+		r.resizeSeason(stored.seasons[len(stored.seasons)-1], season)
+	}
+	r.movies[stored.id] = stored
+
+	return r.movieDetail(userID, stored.id), nil
+}
+
+// UpdateMovie function update selected movie, when seasons layout is given seasons are changed to match it,
+// when Tags are given they replace tags of movie
+func (r *MemoryRepository) UpdateMovie(userID int64, movieID int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+	if payload.HasPartialSeasonsLayout() {
+		return movie, ErrPartialSeasonsLayout
+	}
+
+	seasons := payload.SeasonsLayout()
+	if len(seasons) > 0 {
+		err = validateSeasonsLayout(seasons)
+		if err != nil {
+			return movie, err
+		}
+	}
+
+	tags, err := normalizeTags(payload.Tags)
+	if err != nil {
+		return movie, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := r.movie(userID, movieID)
+	if stored == nil {
+		return movie, ErrMovieNotFound
+	}
+	if r.nameTaken(userID, payload.MovieName, movieID) {
+		return movie, ErrDuplicateMovieName
+	}
+
+	stored.name = payload.MovieName
+	stored.url = payload.URL
+	if payload.Tags != nil {
+		stored.tags = tags
+	}
+
+	if len(seasons) > 0 {
+		if len(stored.seasons) > len(seasons) {
+			stored.seasons = stored.seasons[:len(seasons)]
+		}
+		for i, season := range seasons {
+			if i >= len(stored.seasons) {
+				stored.seasons = append(stored.seasons, &memorySeason{})
+			}
+			r.resizeSeason(stored.seasons[i], season)
+		}
+	}
+
+	return r.movieDetail(userID, movieID), nil
+}
+
+// DeleteMovie function permanently remove movie of user (also one in trash) with its seasons and episodes
+// from memory, ErrMovieNotFound is returned when user has no such movie
+func (r *MemoryRepository) DeleteMovie(userID int64, movieID int64) (deletion models.MovieDeletion, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.movies[movieID]
+	if !ok || stored.userID != userID {
+		return deletion, ErrMovieNotFound
+	}
+	return r.deleteMovie(stored), nil
+}
+
+// TrashMovie function move movie of user to trash, it stays hidden until it is restored or purged
+func (r *MemoryRepository) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := r.movie(userID, movieID)
+	if stored == nil {
+		return item, ErrMovieNotFound
+	}
+
+	now := time.Now()
+	stored.deletedAt = &now
+	return models.TrashItem{ID: stored.id, Name: stored.name, URL: stored.url, DeletedAt: now}, nil
+}
+
+// RestoreMovie function take movie of user back from trash, ErrMovieNotFound is returned when it is not in trash
+func (r *MemoryRepository) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, ok := r.movies[movieID]
+	if !ok || stored.userID != userID || stored.deletedAt == nil {
+		return movie, ErrMovieNotFound
+	}
+	if r.nameTaken(userID, stored.name, movieID) {
+		return movie, ErrRestoreNameTaken
+	}
+
+	stored.deletedAt = nil
+	return r.movieDetail(userID, movieID), nil
+}
+
+// RetrieveTrash function return movies of user in trash, recently deleted first
+func (r *MemoryRepository) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	items = models.TrashItems{}
+	for _, movie := range r.sortedMovies() {
+		if movie.userID == userID && movie.deletedAt != nil {
+			items = append(items, models.TrashItem{ID: movie.id, Name: movie.name, URL: movie.url, DeletedAt: *movie.deletedAt})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+// PurgeTrash function permanently delete movies of all users moved to trash before deletedBefore
+func (r *MemoryRepository) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, movie := range r.movies {
+		if movie.deletedAt == nil || !movie.deletedAt.Before(deletedBefore) {
+			continue
+		}
+		removed := r.deleteMovie(movie)
+		deletion.Movies += removed.Movies
+		deletion.Seasons += removed.Seasons
+		deletion.Episodes += removed.Episodes
+	}
+	return deletion, nil
+}
+
+// deleteMovie remove movie from memory and return number of removed movies, seasons and episodes
+func (r *MemoryRepository) deleteMovie(stored *memoryMovie) (deletion models.MovieDeletion) {
+	deletion.Movies = 1
+	deletion.Seasons = int64(len(stored.seasons))
+	for _, season := range stored.seasons {
+		deletion.Episodes += int64(len(season.episodes))
+	}
+	delete(r.movies, stored.id)
+	return deletion
+}
+
+// SetEpisodeWatched function mark selected episode as watched (with current date) or unwatched
+func (r *MemoryRepository) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.movie(userID, movieID) == nil {
+		return movie, ErrMovieNotFound
+	}
+
+	season := r.season(userID, movieID, seasonNumber)
+	if season == nil || episodeNumber < 1 || episodeNumber > len(season.episodes) {
+		return movie, ErrEpisodeNotFound
+	}
+
+	markMemoryEpisode(season.episodes[episodeNumber-1], watched)
+	return r.movieDetail(userID, movieID), nil
+}
+
+// WatchNextEpisode function mark as watched first unwatched episode placed after the last watched one.
+// When there is no such episode returned movie has Finished flag set.
+func (r *MemoryRepository) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored := r.movie(userID, movieID)
+	if stored == nil {
+		return movie, ErrMovieNotFound
+	}
+	movie = r.movieDetail(userID, movieID)
+
+	next := nextMemoryEpisode(stored, movie.LastWatchedEpisode)
+	if next == nil {
+		return movie, nil
+	}
+
+	markMemoryEpisode(next, true)
+	return r.movieDetail(userID, movieID), nil
+}
+
+// nextMemoryEpisode return first unwatched episode placed after the last watched one, nil when there is no such episode
+func nextMemoryEpisode(stored *memoryMovie, lastWatched models.Episode) *memoryEpisode {
+	for seasonIndex, season := range stored.seasons {
+		for episodeIndex, episode := range season.episodes {
+			seasonNumber, episodeNumber := seasonIndex+1, episodeIndex+1
+			after := seasonNumber > lastWatched.Series ||
+				(seasonNumber == lastWatched.Series && episodeNumber > lastWatched.EpisodeNumber)
+			if after && !episode.watched {
+				return episode
+			}
+		}
+	}
+	return nil
+}
+
+// RetrieveSeasons function return all seasons of movie with number of watched episodes
+func (r *MemoryRepository) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stored := r.movie(userID, movieID)
+	if stored == nil {
+		return seasons, ErrMovieNotFound
+	}
+
+	seasons = models.Seasons{}
+
+	for i, storedSeason := range stored.seasons {
+		season := models.Season{Number: i + 1, EpisodesCount: len(storedSeason.episodes)}
+		for _, episode := range storedSeason.episodes {
+			if episode.watched {
+				season.WatchedCount++
+			}
+		}
+		if season.EpisodesCount > 0 {
+			season.Progress = float64(season.WatchedCount) * 100 / float64(season.EpisodesCount)
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, nil
+}
+
+// RetrieveEpisodes function return watch state of all episodes in selected season
+func (r *MemoryRepository) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if r.movie(userID, movieID) == nil {
+		return episodes, ErrMovieNotFound
+	}
+
+	season := r.season(userID, movieID, seasonNumber)
+	if season == nil {
+		return episodes, ErrSeasonNotFound
+	}
+
+	episodes = models.EpisodeStates{}
+	for i, episode := range season.episodes {
+		episodes = append(episodes, models.EpisodeState{
+			Number:  i + 1,
+			Title:   episode.title,
+			AirDate: episode.airDate,
+			Watched: episode.watched,
+			Date:    episode.date,
+		})
+	}
+	return episodes, nil
+}
+
+// CreateUser store new user in memory, the first registered user is admin and becomes owner
+// of movies without owner, the following ones are editors
+func (r *MemoryRepository) CreateUser(username string, passwordHash string) (user models.User, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == username {
+			return user, ErrDuplicateUserName
+		}
+	}
+
+	user = models.User{ID: int64(len(r.users) + 1), Username: username, Role: models.RoleEditor, PasswordHash: passwordHash}
+	if user.ID == 1 {
+		user.Role = models.RoleAdmin
+	}
+	r.users = append(r.users, user)
+
+	if user.ID == 1 {
+		for _, movie := range r.movies {
+			if movie.userID == 0 {
+				movie.userID = user.ID
+			}
+		}
+	}
+	return user, nil
+}
+
+// RetrieveUserByName found user with selected username
+func (r *MemoryRepository) RetrieveUserByName(username string) (user models.User, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return user, ErrUserNotFound
+}
+
+// UpdateUserRole change role of user with selected username
+func (r *MemoryRepository) UpdateUserRole(username string, role string) (user models.User, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := range r.users {
+		if r.users[i].Username == username {
+			r.users[i].Role = role
+			return r.users[i], nil
+		}
+	}
+	return user, ErrUserNotFound
+}
+
+func (r *MemoryRepository) sortedMovies() []*memoryMovie {
+	movies := make([]*memoryMovie, 0, len(r.movies))
+	for _, movie := range r.movies {
+		movies = append(movies, movie)
+	}
+	sort.Slice(movies, func(i, j int) bool { return movies[i].id < movies[j].id })
+	return movies
+}
+
+func (r *MemoryRepository) nameTaken(userID int64, name string, exceptMovieID int64) bool {
+	for _, movie := range r.movies {
+		if movie.userID == userID && movie.name == name && movie.id != exceptMovieID && movie.deletedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// movie return movie of user or nil when it does not exist, belongs to other user or is in trash
+func (r *MemoryRepository) movie(userID int64, movieID int64) *memoryMovie {
+	stored, ok := r.movies[movieID]
+	if !ok || stored.userID != userID || stored.deletedAt != nil {
+		return nil
+	}
+	return stored
+}
+
+func (r *MemoryRepository) season(userID int64, movieID int64, seasonNumber int) *memorySeason {
+	stored := r.movie(userID, movieID)
+	if stored == nil || seasonNumber < 1 || seasonNumber > len(stored.seasons) {
+		return nil
+	}
+	return stored.seasons[seasonNumber-1]
+}
+
+// resizeSeason append missing episodes (with details from payload) or remove the ones above new size
+func (r *MemoryRepository) resizeSeason(season *memorySeason, payload models.SeasonPayload) {
+	count := payload.EpisodesCount()
+	if len(season.episodes) > count {
+		season.episodes = season.episodes[:count]
+	}
+
+	for episodeNumber := len(season.episodes) + 1; episodeNumber <= count; episodeNumber++ {
+		r.lastEpisodeID++
+		episode := &memoryEpisode{id: r.lastEpisodeID}
+		if episodeNumber <= len(payload.EpisodeDetails) {
+			episode.title = payload.EpisodeDetails[episodeNumber-1].Title
+			episode.airDate = payload.EpisodeDetails[episodeNumber-1].AirDate
+		}
+		season.episodes = append(season.episodes, episode)
+	}
+}
+
+func (r *MemoryRepository) movieDetail(userID int64, movieID int64) (movie models.MovieDetail) {
+	stored := r.movie(userID, movieID)
+	if stored == nil {
+		return movie
+	}
+
+	movie.ID = stored.id
+	movie.Name = stored.name
+	movie.URL = stored.url
+	movie.SeriesCount = len(stored.seasons)
+	movie.Tags = append([]string{}, stored.tags...)
+
+	for seasonIndex, season := range stored.seasons {
+		for episodeIndex, episode := range season.episodes {
+			if !episode.watched || episode.date == nil || episode.date.Before(movie.DateOfLastWatchedEpisode) {
+				continue
+			}
+			movie.LastWatchedEpisode = models.Episode{ID: int(episode.id), Series: seasonIndex + 1, EpisodeNumber: episodeIndex + 1}
+			movie.DateOfLastWatchedEpisode = *episode.date
+		}
+	}
+	// movie without episodes has no watched episode, so it is never finished
+	movie.Finished = movie.LastWatchedEpisode.ID != 0 && nextMemoryEpisode(stored, movie.LastWatchedEpisode) == nil
+	return movie
+}
+
+func markMemoryEpisode(episode *memoryEpisode, watched bool) {
+	episode.watched = watched
+	episode.date = nil
+	if watched {
+		now := time.Now()
+		episode.date = &now
+	}
+}
+
+// likeMatch check if value match SQL LIKE pattern with % and _ wildcards. It is greedy matcher which goes back
+// only to the last %, so it takes O(len(pattern)*len(value)) time also for patterns with many wildcards.
+func likeMatch(pattern string, value string) bool {
+	patternRunes, valueRunes := []rune(pattern), []rune(value)
+	p, v := 0, 0
+	lastPercent, matchedByPercent := -1, 0
+
+	for v < len(valueRunes) {
+		switch {
+		case p < len(patternRunes) && patternRunes[p] == '%':
+			lastPercent, matchedByPercent = p, v
+			p++
+		case p < len(patternRunes) && (patternRunes[p] == '_' || patternRunes[p] == valueRunes[v]):
+			p++
+			v++
+		case lastPercent >= 0:
+			// let the last % take one more character and match the rest of pattern again
+			matchedByPercent++
+			p, v = lastPercent+1, matchedByPercent
+		default:
+			return false
+		}
+	}
+
+	for p < len(patternRunes) && patternRunes[p] == '%' {
+		p++
+	}
+	return p == len(patternRunes)
+}
+
+// RetrieveStats count users, shows and episodes of all users, EpisodesWatchedSince counts episodes
+// watched not earlier than since
+func (r *MemoryRepository) RetrieveStats(since time.Time) (stats models.Stats, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stats.Users = int64(len(r.users))
+	for _, movie := range r.movies {
+		if movie.deletedAt != nil {
+			continue
+		}
+		stats.Shows++
+		for _, season := range movie.seasons {
+			for _, episode := range season.episodes {
+				stats.Episodes++
+				if !episode.watched {
+					continue
+				}
+				stats.WatchedEpisodes++
+				if episode.date != nil && !episode.date.Before(since) {
+					stats.EpisodesWatchedSince++
+				}
+			}
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+func TestMemoryCreateAndWatchMovie(t *testing.T) {
+	testCreateAndWatchMovie(t, NewMemoryRepository())
+}
+
+func TestMemoryFinishedMovie(t *testing.T) {
+	testFinishedMovie(t, NewMemoryRepository())
+}
+
+func TestMemoryUpdateSeasonsLayout(t *testing.T) {
+	testUpdateSeasonsLayout(t, NewMemoryRepository())
+}
+
+func TestMemoryRetrieveMovieItems(t *testing.T) {
+	testRetrieveMovieItems(t, NewMemoryRepository())
+}
+
+func TestMemoryMovieListQuery(t *testing.T) {
+	testMovieListQuery(t, NewMemoryRepository())
+}
+
+func TestMemoryMovieTags(t *testing.T) {
+	testMovieTags(t, NewMemoryRepository())
+}
+
+func TestMemoryRetrieveMovieItemsLimitAndSkip(t *testing.T) {
+	repository := NewMemoryRepository()
+	userID := createTestUser(t, repository, "john")
+	for _, name := range []string{"Arrow", "Flash", "Legends", "Supergirl"} {
+		repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: name})
+	}
+
+	movies, err := repository.RetrieveMovieItems(userID, models.MovieListQuery{SearchString: "%", Limit: 2, Skip: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(movies) != 2 || movies[0].Name != "Flash" || movies[1].Name != "Legends" {
+		t.Errorf("Wrong movies, expected 'Flash' and 'Legends', got %v", movies)
+	}
+}
+
+func TestMemoryCreateMovieDuplicatedName(t *testing.T) {
+	repository := NewMemoryRepository()
+	userID := createTestUser(t, repository, "john")
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+
+	_, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+	if err != ErrDuplicateMovieName {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrDuplicateMovieName, err)
+	}
+}
+
+func TestMemoryCreateMovieInvalidLayout(t *testing.T) {
+	repository := NewMemoryRepository()
+	userID := createTestUser(t, repository, "john")
+
+	_, err := repository.CreateMovie(userID, models.MovieCreationPayload{
+		MovieName: "Arrow",
+		Seasons:   []models.SeasonPayload{{Number: 2, Episodes: 1}},
+	})
+	if err != ErrInvalidSeasonLayout {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrInvalidSeasonLayout, err)
+	}
+}
+
+func TestMemoryCreateMovieTooManyEpisodes(t *testing.T) {
+	repository := NewMemoryRepository()
+	userID := createTestUser(t, repository, "john")
+
+	_, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 100, EpisodesInSeries: 500})
+	if err != ErrTooManyEpisodes {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrTooManyEpisodes, err)
+	}
+}
+
+func TestMemoryNotExistingMovie(t *testing.T) {
+	testMovieNotFound(t, NewMemoryRepository())
+}
+
+func TestMemoryTrash(t *testing.T) {
+	testTrash(t, NewMemoryRepository())
+}
+
+func TestMemoryTrashFreesName(t *testing.T) {
+	testTrashFreesName(t, NewMemoryRepository())
+}
+
+func TestMemoryDeleteMovie(t *testing.T) {
+	testDeleteMovie(t, NewMemoryRepository())
+}
+
+func TestMemoryConcurrentWatch(t *testing.T) {
+	repository := NewMemoryRepository()
+	userID := createTestUser(t, repository, "john")
+	movie, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 50})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repository.WatchNextEpisode(userID, movie.ID)
+		}()
+	}
+	wg.Wait()
+
+	seasons, _ := repository.RetrieveSeasons(userID, movie.ID)
+	if seasons[0].WatchedCount != 50 {
+		t.Errorf("Wrong watched count, expected 50, got %d", seasons[0].WatchedCount)
+	}
+}
+
+func TestLikeMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"%", "", true},
+		{"%row%", "arrow", true},
+		{"arr_w", "arrow", true},
+		{"arr_", "arrow", false},
+		{"%flash%", "arrow", false},
+		{"", "", true},
+		{"", "arrow", false},
+		{"a%w", "arrow", true},
+		{"a%r%w", "arrow", true},
+		{"%r_w", "arrow", true},
+		{"%%r%%", "arrow", true},
+		{"a%o", "arrow", false},
+		{"_", "ł", true},
+		{"%a%a%a%a%b", strings.Repeat("a", 150), false},
+		{"%a%a%a%a%a%a%a%a%a%a%b", strings.Repeat("a", 150) + "b", true},
+	}
+
+	for _, c := range cases {
+		if likeMatch(c.pattern, c.value) != c.match {
+			t.Errorf("likeMatch(%q, %q) expected %v", c.pattern, c.value, c.match)
+		}
+	}
+}
+
+func TestMemoryUserScoping(t *testing.T) {
+	testUserScoping(t, NewMemoryRepository())
+}
+
+func TestMemoryUsers(t *testing.T) {
+	testUsers(t, NewMemoryRepository())
+}
+
+func TestMemoryStats(t *testing.T) {
+	testStats(t, NewMemoryRepository())
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// movieProgressTable count episodes, watched episodes and date of the last watched episode of every movie
+const movieProgressTable = "SELECT season.serial_id, COUNT(episode.id) AS episodes, SUM(CASE WHEN episode.watched = 1 THEN 1 ELSE 0 END) AS watched, MAX(episode.date) AS last_watched FROM season JOIN episode ON episode.season_id = season.id GROUP BY season.serial_id"
+
+// movieStatusConditions translate watch status to condition on movieProgressTable
+var movieStatusConditions = map[string]string{
+	models.MovieStatusFinished:   "COALESCE(progress.episodes, 0) > 0 AND progress.watched = progress.episodes",
+	models.MovieStatusInProgress: "progress.watched > 0 AND progress.watched < progress.episodes",
+	models.MovieStatusNotStarted: "COALESCE(progress.watched, 0) = 0",
+}
+
+// movieSortColumns is whitelist of expressions movie list can be sorted by, movies without
+// watched episode are placed at the end when they are sorted by last watched episode
+var movieSortColumns = map[string]string{
+	"":                          "tv_series.id",
+	models.MovieSortCreated:     "tv_series.id",
+	models.MovieSortName:        "tv_series.name",
+	models.MovieSortLastWatched: "progress.last_watched IS NULL, progress.last_watched",
+	models.MovieSortProgress:    "COALESCE(progress.watched * 1.0 / NULLIF(progress.episodes, 0), 0)",
+}
+
+var sortOrders = map[string]string{"": "ASC", "asc": "ASC", "desc": "DESC"}
+
+// RetrieveMovieItems return movies of user selected and sorted by query, ErrInvalidMovieListQuery is returned
+// for unknown status, sort column or order
+func (r *SQLRepository) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	column, knownColumn := movieSortColumns[query.Sort]
+	order, knownOrder := sortOrders[query.Order]
+	if !knownColumn || !knownOrder {
+		return movies, ErrInvalidMovieListQuery
+	}
+
+	conditions := []string{"tv_series.user_id = ?", "tv_series.deleted_at IS NULL"}
+	args := []interface{}{userID}
+	if query.SearchString != "" {
+		conditions = append(conditions, "tv_series.name "+r.dialect.like+" ?")
+		args = append(args, query.SearchString)
+	}
+	if query.Status != "" {
+		condition, ok := movieStatusConditions[query.Status]
+		if !ok {
+			return movies, ErrInvalidMovieListQuery
+		}
+		conditions = append(conditions, condition)
+	}
+	if query.HasUnwatched != nil {
+		comparison := "="
+		if *query.HasUnwatched {
+			comparison = "<"
+		}
+		conditions = append(conditions, "COALESCE(progress.watched, 0) "+comparison+" COALESCE(progress.episodes, 0)")
+	}
+	if query.WatchedSince != nil {
+		conditions = append(conditions, "progress.last_watched >= ?")
+		args = append(args, *query.WatchedSince)
+	}
+	if query.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM tag WHERE tag.serial_id = tv_series.id AND tag.name = ?)")
+		args = append(args, normalizeTag(query.Tag))
+	}
+
+	statement := "SELECT tv_series.id, tv_series.name, COALESCE(tv_series.url, ''), COALESCE(progress.episodes, 0), COALESCE(progress.watched, 0) " +
+		"FROM tv_series LEFT JOIN (" + movieProgressTable + ") progress ON progress.serial_id = tv_series.id " +
+		"WHERE " + strings.Join(conditions, " AND ") + " " +
+		"ORDER BY " + column + " " + order + ", tv_series.id " + order + " LIMIT ? OFFSET ?;"
+	rows, err := r.query(statement, append(args, query.Limit, query.Skip)...)
+	if err != nil {
+		return movies, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie models.MovieItem
+		var episodes, watched int
+
+		err = rows.Scan(&movie.ID, &movie.Name, &movie.URL, &episodes, &watched)
+		if err != nil {
+			return movies, err
+		}
+		movie.Progress = progressPercent(watched, episodes)
+		movies = append(movies, movie)
+	}
+	return movies, rows.Err()
+}
+
+// progressPercent return percent of watched episodes, movie without episodes has 0 progress
+func progressPercent(watched int, episodes int) float64 {
+	if episodes == 0 {
+		return 0
+	}
+	return float64(watched) * 100 / float64(episodes)
+}
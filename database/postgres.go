@@ -0,0 +1,10 @@
+package database
+
+import "database/sql"
+
+var postgresDialect = sqlDialect{numberedPlaceholders: true, returningID: true, like: "ILIKE", lockUsers: "LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE;"}
+
+// NewPostgresRepository create repository working on given PostgreSQL database connection
+func NewPostgresRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, dialect: postgresDialect}
+}
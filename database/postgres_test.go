@@ -0,0 +1,109 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	repository := NewPostgresRepository(nil)
+
+	query := repository.rebind("SELECT id FROM season WHERE serial_id = ? AND number = ?;")
+
+	if query != "SELECT id FROM season WHERE serial_id = $1 AND number = $2;" {
+		t.Errorf("Wrong query, got %s", query)
+	}
+}
+
+func TestMySQLRebind(t *testing.T) {
+	repository := NewMySQLRepository(nil)
+
+	query := repository.rebind("SELECT id FROM season WHERE serial_id = ? AND number = ?;")
+
+	if query != "SELECT id FROM season WHERE serial_id = ? AND number = ?;" {
+		t.Errorf("Wrong query, got %s", query)
+	}
+}
+
+func TestPostgresExecuteStmtInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	repository := NewPostgresRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO season \\(serial_id, number\\) VALUES \\(\\$1, \\$2\\) RETURNING id;")
+	mock.ExpectQuery("(.+)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	tx, _ := db.Begin()
+
+	id, err := repository.executeStmt(tx, "INSERT INTO season (serial_id, number) VALUES (?, ?);", 1, 2)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if id != 12 {
+		t.Errorf("Wrong id, expected 12, got %d", id)
+	}
+}
+
+func TestPostgresExecuteStmtUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	repository := NewPostgresRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE tv_series SET name = \\$1, url = \\$2 WHERE id = \\$3;")
+	mock.ExpectExec("(.+)").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("no LastInsertId available")))
+
+	tx, _ := db.Begin()
+
+	id, err := repository.executeStmt(tx, "UPDATE tv_series SET name = ?, url = ? WHERE id = ?;", "Test movie", "http://www.example.com", 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if id != 0 {
+		t.Errorf("Wrong id, expected 0, got %d", id)
+	}
+}
+
+func TestCreateUserDuplicateInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	repository := NewPostgresRepository(db)
+
+	// other registration of the same name committed after this one counted users
+	mock.ExpectBegin()
+	mock.ExpectExec("LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE;").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\)(.+) FROM users;").
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"count", "same"}).AddRow(1, 0))
+	mock.ExpectPrepare("INSERT INTO users (.+) RETURNING id;")
+	mock.ExpectQuery("(.+)").
+		WillReturnError(fmt.Errorf("pq: duplicate key value violates unique constraint \"users_username_key\""))
+	mock.ExpectRollback()
+
+	_, err = repository.CreateUser("john", "hash")
+
+	if err != ErrDuplicateUserName {
+		t.Errorf("Expected ErrDuplicateUserName, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Registration was not serialized, %s", err)
+	}
+}
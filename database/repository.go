@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// ErrEpisodeNotFound is returned when selected episode does not exist in movie
+var ErrEpisodeNotFound = apperror.NotFound("Episode not found")
+
+// ErrSeasonNotFound is returned when selected season does not exist in movie
+var ErrSeasonNotFound = apperror.NotFound("Season not found")
+
+// ErrInvalidSeasonLayout is returned when seasons in payload are not numbered from 1 without gaps
+// or have negative number of episodes
+var ErrInvalidSeasonLayout = apperror.ValidationFailed("Seasons have to be numbered from 1 without gaps and have non negative number of episodes")
+
+// ErrPartialSeasonsLayout is returned when update payload has only one of SeriesNumber and EpisodesInSeries
+var ErrPartialSeasonsLayout = apperror.ValidationFailed("SeriesNumber and EpisodesInSeries have to be given together")
+
+// ErrWatchNextConflict is returned when next episode was repeatedly marked by concurrent requests
+var ErrWatchNextConflict = apperror.Conflict("Next episode is being marked by other request, try again")
+
+// ErrTooManyEpisodes is returned when seasons in payload have together more than models.MaxEpisodesPerMovie episodes
+var ErrTooManyEpisodes = apperror.ValidationFailed(fmt.Sprintf("Movie can have at most %d episodes", models.MaxEpisodesPerMovie))
+
+// ErrUserNotFound is returned when there is no user with selected username
+var ErrUserNotFound = apperror.NotFound("User not found")
+
+// ErrDuplicateUserName is returned when user with the same username already exists
+var ErrDuplicateUserName = apperror.Conflict("User with this username already exists")
+
+// ErrDuplicateMovieName is returned when user already has movie with the same name
+var ErrDuplicateMovieName = apperror.Conflict("Movie with this name already exists")
+
+// ErrRestoreNameTaken is returned when movie can not be restored from trash, because other movie has its name
+var ErrRestoreNameTaken = apperror.Conflict("Movie with this name already exists, rename or delete it before restoring")
+
+// ErrMovieNotFound is returned when movie does not exist or it belongs to other user
+var ErrMovieNotFound = apperror.NotFound("Movie not found")
+
+// ErrInvalidTag is returned when tag of movie is empty or longer than 50 characters
+var ErrInvalidTag = apperror.ValidationFailed("Tags have to be from 1 to 50 characters long")
+
+// ErrInvalidMovieListQuery is returned when movie list is filtered by unknown status or sorted by unknown column
+var ErrInvalidMovieListQuery = apperror.ValidationFailed("Unknown status, sort column or order of movie list")
+
+// MovieRepository describe all operations on stored movies, seasons and episodes.
+// Every operation is done on behalf of user given by userID, movies of other users are not visible.
+// Operations on single movie return ErrMovieNotFound when movie does not exist or belongs to other user.
+// Movies moved to trash are treated as not existing, only trash operations and DeleteMovie can see them.
+type MovieRepository interface {
+	RetrieveMovieItems(userID int64, query models.MovieListQuery) (models.MovieItems, error)
+	RetrieveMovieDetail(userID int64, movieID int64) (models.MovieDetail, error)
+	CreateMovie(userID int64, payload models.MovieCreationPayload) (models.MovieDetail, error)
+	UpdateMovie(userID int64, movieID int64, payload models.MovieUpdatePayload) (models.MovieDetail, error)
+	DeleteMovie(userID int64, movieID int64) (models.MovieDeletion, error)
+
+	TrashMovie(userID int64, movieID int64) (models.TrashItem, error)
+	RestoreMovie(userID int64, movieID int64) (models.MovieDetail, error)
+	RetrieveTrash(userID int64) (models.TrashItems, error)
+	// PurgeTrash permanently delete movies of all users moved to trash before deletedBefore
+	PurgeTrash(deletedBefore time.Time) (models.MovieDeletion, error)
+
+	SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (models.MovieDetail, error)
+	WatchNextEpisode(userID int64, movieID int64) (models.MovieDetail, error)
+	RetrieveSeasons(userID int64, movieID int64) (models.Seasons, error)
+	RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (models.EpisodeStates, error)
+}
+
+// UserRepository describe operations on user accounts
+type UserRepository interface {
+	CreateUser(username string, passwordHash string) (models.User, error)
+	RetrieveUserByName(username string) (models.User, error)
+	UpdateUserRole(username string, role string) (models.User, error)
+}
+
+// StatsRepository describe counting of data of all users
+type StatsRepository interface {
+	RetrieveStats(since time.Time) (models.Stats, error)
+}
+
+// Repository join together storage of user accounts and their movies
+type Repository interface {
+	MovieRepository
+	UserRepository
+	StatsRepository
+}
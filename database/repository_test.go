@@ -0,0 +1,637 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+func testCreateAndWatchMovie(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	airDate := models.NewDate(time.Date(2017, 11, 21, 0, 0, 0, 0, time.UTC))
+
+	payload := models.MovieCreationPayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+		Seasons: []models.SeasonPayload{
+			{Number: 1, EpisodeDetails: []models.EpisodePayload{{Title: "Pilot", AirDate: &airDate}, {Title: "Second"}}},
+			{Number: 2, Episodes: 1},
+		},
+	}
+
+	movie, err := repository.CreateMovie(userID, payload)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.Name != "Test movie" || movie.SeriesCount != 2 {
+		t.Errorf("Wrong movie, expected 'Test movie' with 2 seasons, got %v", movie)
+	}
+
+	movie, err = repository.SetEpisodeWatched(userID, movie.ID, 1, 2, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.LastWatchedEpisode.Series != 1 || movie.LastWatchedEpisode.EpisodeNumber != 2 {
+		t.Errorf("Wrong last watched episode, expected 1x2, got %v", movie.LastWatchedEpisode)
+	}
+
+	movie, err = repository.WatchNextEpisode(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.LastWatchedEpisode.Series != 2 || movie.LastWatchedEpisode.EpisodeNumber != 1 {
+		t.Errorf("Wrong last watched episode, expected 2x1, got %v", movie.LastWatchedEpisode)
+	}
+
+	movie, err = repository.WatchNextEpisode(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !movie.Finished {
+		t.Error("Movie is not finished, expected finished")
+	}
+
+	seasons, err := repository.RetrieveSeasons(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(seasons) != 2 || seasons[0].WatchedCount != 1 || seasons[1].Progress != 100 {
+		t.Errorf("Wrong seasons, got %v", seasons)
+	}
+
+	episodes, err := repository.RetrieveEpisodes(userID, movie.ID, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(episodes) != 2 || episodes[0].Title != "Pilot" || episodes[0].Watched || !episodes[1].Watched || episodes[1].Date == nil {
+		t.Errorf("Wrong episodes, got %v", episodes)
+	}
+
+	if episodes[0].AirDate == nil || episodes[0].AirDate.Format(models.DateLayout) != "2017-11-21" || episodes[1].AirDate != nil {
+		t.Errorf("Wrong air dates of episodes, got %v and %v", episodes[0].AirDate, episodes[1].AirDate)
+	}
+}
+
+func testFinishedMovie(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	movie, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 2})
+	if movie.Finished {
+		t.Errorf("Movie without watched episodes is finished, got %v", movie)
+	}
+
+	repository.SetEpisodeWatched(userID, movie.ID, 1, 1, true)
+
+	movie, err := repository.RetrieveMovieDetail(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.Finished {
+		t.Errorf("Movie with unwatched episode is finished, got %v", movie)
+	}
+
+	repository.SetEpisodeWatched(userID, movie.ID, 1, 2, true)
+	movie, _ = repository.RetrieveMovieDetail(userID, movie.ID)
+	if !movie.Finished {
+		t.Errorf("Fully watched movie is not finished, got %v", movie)
+	}
+
+	empty, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Empty"})
+	empty, err = repository.WatchNextEpisode(userID, empty.ID)
+	if err != nil || empty.Finished {
+		t.Errorf("Movie without episodes should not be finished, got %v, %v", empty, err)
+	}
+}
+
+func testUpdateSeasonsLayout(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+
+	movie, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Test movie", SeriesNumber: 2, EpisodesInSeries: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = repository.SetEpisodeWatched(userID, movie.ID, 1, 1, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie renewed",
+		Seasons: []models.SeasonPayload{
+			{Number: 1, Episodes: 2},
+			{Number: 2, Episodes: 3},
+			{Number: 3, Episodes: 4},
+		},
+	}
+
+	movie, err = repository.UpdateMovie(userID, movie.ID, payload)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.Name != "Test movie renewed" || movie.SeriesCount != 3 {
+		t.Errorf("Wrong movie, expected 'Test movie renewed' with 3 seasons, got %v", movie)
+	}
+
+	seasons, err := repository.RetrieveSeasons(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if seasons[0].EpisodesCount != 2 || seasons[0].WatchedCount != 1 || seasons[2].EpisodesCount != 4 {
+		t.Errorf("Wrong seasons after update, got %v", seasons)
+	}
+
+	payload.Seasons = payload.Seasons[:1]
+	movie, err = repository.UpdateMovie(userID, movie.ID, payload)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if movie.SeriesCount != 1 {
+		t.Errorf("Wrong series count, expected 1, got %d", movie.SeriesCount)
+	}
+
+	_, err = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Test movie", SeriesNumber: 3})
+	if err != ErrPartialSeasonsLayout {
+		t.Errorf("Update with SeriesNumber alone should be rejected, got error %v", err)
+	}
+
+	seasons, _ = repository.RetrieveSeasons(userID, movie.ID)
+	if len(seasons) != 1 || seasons[0].EpisodesCount != 2 || seasons[0].WatchedCount != 1 {
+		t.Errorf("Seasons were changed by rejected update, got %v", seasons)
+	}
+
+	movie, err = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Test movie", SeriesNumber: 3, EpisodesInSeries: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	seasons, _ = repository.RetrieveSeasons(userID, movie.ID)
+	if movie.SeriesCount != 3 || len(seasons) != 3 || seasons[0].EpisodesCount != 5 || seasons[0].WatchedCount != 1 || seasons[2].EpisodesCount != 5 {
+		t.Errorf("Seasons were not changed by seriesNumber and episodesInSeries, got %v", seasons)
+	}
+
+	movie, _ = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Test movie"})
+	if movie.SeriesCount != 3 {
+		t.Errorf("Seasons were changed by update without layout, got %d seasons", movie.SeriesCount)
+	}
+}
+
+func testRetrieveMovieItems(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Marvel Agents of Shield"})
+
+	movies, err := repository.RetrieveMovieItems(userID, models.MovieListQuery{SearchString: "%Arrow%", Limit: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(movies) != 1 || movies[0].Name != "Arrow" {
+		t.Errorf("Wrong movies, expected only 'Arrow', got %v", movies)
+	}
+}
+
+func testMovieListQuery(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	arrow, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 3, Tags: []string{"Drama", "dc "}})
+	flash, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Flash", SeriesNumber: 1, EpisodesInSeries: 2, Tags: []string{"dc"}})
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Legends", SeriesNumber: 1, EpisodesInSeries: 2})
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Empty"})
+
+	repository.WatchNextEpisode(userID, arrow.ID)
+	time.Sleep(10 * time.Millisecond)
+	repository.WatchNextEpisode(userID, flash.ID)
+	repository.WatchNextEpisode(userID, flash.ID)
+
+	hasUnwatched, allWatched := true, false
+	hourAgo, inHour := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	cases := []struct {
+		query    models.MovieListQuery
+		expected string
+	}{
+		{models.MovieListQuery{}, "Arrow,Flash,Legends,Empty"},
+		{models.MovieListQuery{Status: models.MovieStatusInProgress}, "Arrow"},
+		{models.MovieListQuery{Status: models.MovieStatusFinished}, "Flash"},
+		{models.MovieListQuery{Status: models.MovieStatusNotStarted}, "Legends,Empty"},
+		{models.MovieListQuery{HasUnwatched: &hasUnwatched}, "Arrow,Legends"},
+		{models.MovieListQuery{HasUnwatched: &allWatched}, "Flash,Empty"},
+		{models.MovieListQuery{WatchedSince: &hourAgo}, "Arrow,Flash"},
+		{models.MovieListQuery{WatchedSince: &inHour}, ""},
+		{models.MovieListQuery{Tag: "DC"}, "Arrow,Flash"},
+		{models.MovieListQuery{Tag: "drama"}, "Arrow"},
+		{models.MovieListQuery{Sort: models.MovieSortName, Order: "desc"}, "Legends,Flash,Empty,Arrow"},
+		{models.MovieListQuery{Sort: models.MovieSortCreated, Order: "desc"}, "Empty,Legends,Flash,Arrow"},
+		{models.MovieListQuery{Sort: models.MovieSortLastWatched, Order: "desc"}, "Flash,Arrow,Empty,Legends"},
+		{models.MovieListQuery{Sort: models.MovieSortLastWatched}, "Arrow,Flash,Legends,Empty"},
+		{models.MovieListQuery{Sort: models.MovieSortProgress, Order: "desc"}, "Flash,Arrow,Empty,Legends"},
+		{models.MovieListQuery{Status: models.MovieStatusInProgress, Sort: models.MovieSortLastWatched, Order: "desc", SearchString: "%r%"}, "Arrow"},
+	}
+
+	for _, c := range cases {
+		c.query.Limit = 10
+		movies, err := repository.RetrieveMovieItems(userID, c.query)
+		if err != nil {
+			t.Fatalf("Unexpected error for %+v: %s", c.query, err)
+		}
+
+		var names []string
+		for _, movie := range movies {
+			names = append(names, movie.Name)
+		}
+		if strings.Join(names, ",") != c.expected {
+			t.Errorf("Wrong movies for %+v, expected %s, got %v", c.query, c.expected, names)
+		}
+	}
+
+	movies, _ := repository.RetrieveMovieItems(userID, models.MovieListQuery{Status: models.MovieStatusInProgress, Limit: 10})
+	if len(movies) != 1 || movies[0].Progress < 33.3 || movies[0].Progress > 33.4 {
+		t.Errorf("Wrong progress of movie, expected 33.3, got %v", movies)
+	}
+
+	_, err := repository.RetrieveMovieItems(userID, models.MovieListQuery{Sort: "id; DROP TABLE tag", Limit: 10})
+	if err != ErrInvalidMovieListQuery {
+		t.Errorf("Wrong error for unknown sort column, expected '%s', got '%v'", ErrInvalidMovieListQuery, err)
+	}
+}
+
+func testMovieTags(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	movie, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", Tags: []string{" Drama", "dc", "drama"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if strings.Join(movie.Tags, ",") != "dc,drama" {
+		t.Errorf("Wrong tags, expected dc and drama, got %v", movie.Tags)
+	}
+
+	movie, _ = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Arrow"})
+	if strings.Join(movie.Tags, ",") != "dc,drama" {
+		t.Errorf("Tags are changed by update without tags, got %v", movie.Tags)
+	}
+
+	movie, _ = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Arrow", Tags: []string{"action"}})
+	if strings.Join(movie.Tags, ",") != "action" {
+		t.Errorf("Tags are not replaced, got %v", movie.Tags)
+	}
+
+	movie, _ = repository.UpdateMovie(userID, movie.ID, models.MovieUpdatePayload{MovieName: "Arrow", Tags: []string{}})
+	if movie.Tags == nil || len(movie.Tags) != 0 {
+		t.Errorf("Tags are not cleared, got %v", movie.Tags)
+	}
+
+	_, err = repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Flash", Tags: []string{" "}})
+	if err != ErrInvalidTag {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrInvalidTag, err)
+	}
+}
+
+func createTestUser(t *testing.T, repository UserRepository, username string) int64 {
+	user, err := repository.CreateUser(username, "hash-of-"+username)
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating user", err)
+	}
+	return user.ID
+}
+
+func testUserScoping(t *testing.T, repository Repository) {
+	johnID := createTestUser(t, repository, "john")
+	janeID := createTestUser(t, repository, "jane")
+
+	johnMovie, err := repository.CreateMovie(johnID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	janeMovie, err := repository.CreateMovie(janeID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 3})
+	if err != nil {
+		t.Fatalf("The same show of two users should be allowed, got error: %s", err)
+	}
+
+	repository.WatchNextEpisode(johnID, johnMovie.ID)
+	repository.WatchNextEpisode(johnID, johnMovie.ID)
+	repository.WatchNextEpisode(janeID, janeMovie.ID)
+
+	johnMovie, _ = repository.RetrieveMovieDetail(johnID, johnMovie.ID)
+	janeMovie, _ = repository.RetrieveMovieDetail(janeID, janeMovie.ID)
+	if johnMovie.LastWatchedEpisode.EpisodeNumber != 2 || janeMovie.LastWatchedEpisode.EpisodeNumber != 1 {
+		t.Errorf("Watch state is not kept per user, got john %v and jane %v", johnMovie.LastWatchedEpisode, janeMovie.LastWatchedEpisode)
+	}
+
+	movie, err := repository.RetrieveMovieDetail(janeID, johnMovie.ID)
+	if err != ErrMovieNotFound || movie.ID != 0 {
+		t.Errorf("Movie of other user is visible, got %v, %v", movie, err)
+	}
+
+	movies, _ := repository.RetrieveMovieItems(janeID, models.MovieListQuery{SearchString: "%", Limit: 10})
+	if len(movies) != 1 || int64(movies[0].ID) != janeMovie.ID {
+		t.Errorf("Wrong movies of user, expected only %d, got %v", janeMovie.ID, movies)
+	}
+
+	_, err = repository.SetEpisodeWatched(janeID, johnMovie.ID, 1, 3, true)
+	if err != ErrMovieNotFound {
+		t.Errorf("Episode of other user movie can be marked, got error %v", err)
+	}
+
+	_, err = repository.RetrieveEpisodes(janeID, johnMovie.ID, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("Episodes of other user movie are visible, got error %v", err)
+	}
+
+	_, updateErr := repository.UpdateMovie(janeID, johnMovie.ID, models.MovieUpdatePayload{MovieName: "Hacked"})
+	_, deleteErr := repository.DeleteMovie(janeID, johnMovie.ID)
+	johnMovie, _ = repository.RetrieveMovieDetail(johnID, johnMovie.ID)
+	if updateErr != ErrMovieNotFound || deleteErr != ErrMovieNotFound || johnMovie.Name != "Arrow" {
+		t.Errorf("Movie of other user was changed, got %v (update error %v, delete error %v)", johnMovie, updateErr, deleteErr)
+	}
+}
+
+func testDeleteMovie(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	movie, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 2, EpisodesInSeries: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	repository.WatchNextEpisode(userID, movie.ID)
+
+	deletion, err := repository.DeleteMovie(userID, movie.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if deletion.Movies != 1 || deletion.Seasons != 2 || deletion.Episodes != 6 {
+		t.Errorf("Wrong number of removed rows, expected 1 movie, 2 seasons and 6 episodes, got %v", deletion)
+	}
+
+	_, err = repository.RetrieveMovieDetail(userID, movie.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie was not deleted, got error %v", err)
+	}
+
+	_, err = repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 1})
+	if err != nil {
+		t.Errorf("Name of deleted movie should be free again, got error %s", err)
+	}
+}
+
+func testTrashFreesName(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+	arrow, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 1})
+	repository.TrashMovie(userID, arrow.ID)
+
+	again, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 1})
+	if err != nil {
+		t.Fatalf("Name of movie in trash should be free, got error %v", err)
+	}
+
+	_, err = repository.RestoreMovie(userID, arrow.ID)
+	if err != ErrRestoreNameTaken {
+		t.Errorf("Movie can be restored while other movie has its name, got error %v", err)
+	}
+
+	_, err = repository.UpdateMovie(userID, again.ID, models.MovieUpdatePayload{MovieName: "Arrow 2"})
+	if err != nil {
+		t.Fatalf("Can not rename movie, got error %v", err)
+	}
+
+	movie, err := repository.RestoreMovie(userID, arrow.ID)
+	if err != nil || movie.Name != "Arrow" {
+		t.Errorf("Wrong restored movie, got %v, %v", movie, err)
+	}
+
+	repository.TrashMovie(userID, again.ID)
+	_, err = repository.UpdateMovie(userID, arrow.ID, models.MovieUpdatePayload{MovieName: "Arrow 2"})
+	if err != nil {
+		t.Errorf("Movie can not be renamed to name of movie in trash, got error %v", err)
+	}
+}
+
+func testTrash(t *testing.T, repository Repository) {
+	johnID := createTestUser(t, repository, "john")
+	janeID := createTestUser(t, repository, "jane")
+	arrow, _ := repository.CreateMovie(johnID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 2, EpisodesInSeries: 3})
+	flash, _ := repository.CreateMovie(johnID, models.MovieCreationPayload{MovieName: "Flash", SeriesNumber: 1, EpisodesInSeries: 1})
+
+	item, err := repository.TrashMovie(johnID, arrow.ID)
+	if err != nil || item.ID != arrow.ID || item.Name != "Arrow" || item.DeletedAt.IsZero() {
+		t.Fatalf("Wrong trashed movie, got %v, %v", item, err)
+	}
+
+	movies, _ := repository.RetrieveMovieItems(johnID, models.MovieListQuery{SearchString: "%", Limit: 10})
+	if len(movies) != 1 || int64(movies[0].ID) != flash.ID {
+		t.Errorf("Movie in trash is listed, got %v", movies)
+	}
+
+	_, err = repository.RetrieveMovieDetail(johnID, arrow.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie in trash is visible, got error %v", err)
+	}
+
+	_, err = repository.RetrieveSeasons(johnID, arrow.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Seasons of movie in trash are visible, got error %v", err)
+	}
+
+	_, err = repository.UpdateMovie(johnID, arrow.ID, models.MovieUpdatePayload{MovieName: "Arrow"})
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie in trash can be updated, got error %v", err)
+	}
+
+	_, err = repository.TrashMovie(johnID, arrow.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie can be moved to trash twice, got error %v", err)
+	}
+
+	trash, err := repository.RetrieveTrash(johnID)
+	if err != nil || len(trash) != 1 || trash[0].ID != arrow.ID {
+		t.Errorf("Wrong trash of user, expected only %d, got %v, %v", arrow.ID, trash, err)
+	}
+
+	trash, _ = repository.RetrieveTrash(janeID)
+	if len(trash) != 0 {
+		t.Errorf("Trash of other user is visible, got %v", trash)
+	}
+
+	_, err = repository.RestoreMovie(janeID, arrow.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie of other user can be restored, got error %v", err)
+	}
+
+	movie, err := repository.RestoreMovie(johnID, arrow.ID)
+	if err != nil || movie.ID != arrow.ID || movie.SeriesCount != 2 {
+		t.Errorf("Wrong restored movie, got %v, %v", movie, err)
+	}
+
+	_, err = repository.RestoreMovie(johnID, arrow.ID)
+	if err != ErrMovieNotFound {
+		t.Errorf("Movie which is not in trash can be restored, got error %v", err)
+	}
+
+	repository.TrashMovie(johnID, arrow.ID)
+	repository.TrashMovie(johnID, flash.ID)
+
+	deletion, err := repository.PurgeTrash(time.Now().Add(-time.Hour))
+	if err != nil || deletion.Movies != 0 {
+		t.Errorf("Recently trashed movies should not be purged, got %v, %v", deletion, err)
+	}
+
+	_, err = repository.DeleteMovie(johnID, flash.ID)
+	if err != nil {
+		t.Errorf("Movie in trash can not be deleted permanently, got error %v", err)
+	}
+
+	deletion, err = repository.PurgeTrash(time.Now().Add(time.Minute))
+	if err != nil || deletion.Movies != 1 || deletion.Seasons != 2 || deletion.Episodes != 6 {
+		t.Errorf("Wrong purge, expected 1 movie, 2 seasons and 6 episodes, got %v, %v", deletion, err)
+	}
+
+	trash, _ = repository.RetrieveTrash(johnID)
+	if len(trash) != 0 {
+		t.Errorf("Trash is not empty after purge, got %v", trash)
+	}
+}
+
+func testMovieNotFound(t *testing.T, repository Repository) {
+	userID := createTestUser(t, repository, "john")
+
+	_, err := repository.RetrieveMovieDetail(userID, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("RetrieveMovieDetail: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.UpdateMovie(userID, 1, models.MovieUpdatePayload{MovieName: "Arrow"})
+	if err != ErrMovieNotFound {
+		t.Errorf("UpdateMovie: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.DeleteMovie(userID, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("DeleteMovie: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.SetEpisodeWatched(userID, 1, 1, 1, true)
+	if err != ErrMovieNotFound {
+		t.Errorf("SetEpisodeWatched: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.WatchNextEpisode(userID, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("WatchNextEpisode: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.RetrieveSeasons(userID, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("RetrieveSeasons: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	_, err = repository.RetrieveEpisodes(userID, 1, 1)
+	if err != ErrMovieNotFound {
+		t.Errorf("RetrieveEpisodes: wrong error, expected '%s', got '%v'", ErrMovieNotFound, err)
+	}
+
+	movie, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	seasons, err := repository.RetrieveSeasons(userID, movie.ID)
+	if err != nil || len(seasons) != 0 {
+		t.Errorf("Expected no seasons of existing movie, got %v, %v", seasons, err)
+	}
+
+	_, err = repository.SetEpisodeWatched(userID, movie.ID, 1, 1, true)
+	if err != ErrEpisodeNotFound {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrEpisodeNotFound, err)
+	}
+
+	_, err = repository.RetrieveEpisodes(userID, movie.ID, 1)
+	if err != ErrSeasonNotFound {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrSeasonNotFound, err)
+	}
+}
+
+func testUsers(t *testing.T, repository Repository) {
+	johnID := createTestUser(t, repository, "john")
+
+	_, err := repository.CreateUser("john", "other-hash")
+	if err != ErrDuplicateUserName {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrDuplicateUserName, err)
+	}
+
+	user, err := repository.RetrieveUserByName("john")
+	if err != nil || user.ID != johnID || user.PasswordHash != "hash-of-john" {
+		t.Errorf("Wrong user, expected john with ID %d, got %v, %v", johnID, user, err)
+	}
+
+	if user.Role != models.RoleAdmin {
+		t.Errorf("Wrong role of first user, expected '%s', got '%s'", models.RoleAdmin, user.Role)
+	}
+
+	_, err = repository.RetrieveUserByName("jane")
+	if err != ErrUserNotFound {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrUserNotFound, err)
+	}
+
+	jane, err := repository.CreateUser("jane", "hash-of-jane")
+	if err != nil || jane.Role != models.RoleEditor {
+		t.Errorf("Wrong role of second user, expected '%s', got %v, %v", models.RoleEditor, jane, err)
+	}
+
+	jane, err = repository.UpdateUserRole("jane", models.RoleViewer)
+	if err != nil || jane.Role != models.RoleViewer {
+		t.Errorf("Wrong role after update, expected '%s', got %v, %v", models.RoleViewer, jane, err)
+	}
+
+	jane, _ = repository.RetrieveUserByName("jane")
+	if jane.Role != models.RoleViewer {
+		t.Errorf("Role was not stored, expected '%s', got '%s'", models.RoleViewer, jane.Role)
+	}
+
+	_, err = repository.UpdateUserRole("bob", models.RoleViewer)
+	if err != ErrUserNotFound {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrUserNotFound, err)
+	}
+}
+
+func testStats(t *testing.T, repository Repository) {
+	johnID := createTestUser(t, repository, "john")
+	janeID := createTestUser(t, repository, "jane")
+
+	arrow, _ := repository.CreateMovie(johnID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 3})
+	flash, _ := repository.CreateMovie(janeID, models.MovieCreationPayload{MovieName: "Flash", SeriesNumber: 2, EpisodesInSeries: 2})
+	repository.WatchNextEpisode(johnID, arrow.ID)
+	repository.WatchNextEpisode(janeID, flash.ID)
+
+	stats, err := repository.RetrieveStats(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := models.Stats{Users: 2, Shows: 2, Episodes: 7, WatchedEpisodes: 2, EpisodesWatchedSince: 2}
+	if stats != expected {
+		t.Errorf("Wrong stats, expected %v, got %v", expected, stats)
+	}
+
+	stats, _ = repository.RetrieveStats(time.Now().Add(time.Hour))
+	if stats.EpisodesWatchedSince != 0 {
+		t.Errorf("Wrong number of episodes watched in future, expected 0, got %d", stats.EpisodesWatchedSince)
+	}
+
+	repository.TrashMovie(johnID, arrow.ID)
+	stats, _ = repository.RetrieveStats(time.Now().Add(-time.Hour))
+	expected = models.Stats{Users: 2, Shows: 1, Episodes: 4, WatchedEpisodes: 1, EpisodesWatchedSince: 1}
+	if stats != expected {
+		t.Errorf("Movie in trash is counted in stats, expected %v, got %v", expected, stats)
+	}
+}
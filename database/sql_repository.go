@@ -0,0 +1,697 @@
+package database
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// SQLRepository is Repository which keeps users and their movies in SQL database (MySQL, SQLite or PostgreSQL),
+// queries are written with ? placeholders and adjusted to database by dialect
+type SQLRepository struct {
+	db      *sql.DB
+	dialect sqlDialect
+}
+
+// sqlDialect describe differences between supported SQL databases
+type sqlDialect struct {
+	numberedPlaceholders bool   // $1, $2, ... instead of ?
+	returningID          bool   // id of inserted row is read by RETURNING id instead of LastInsertId
+	like                 string // case insensitive LIKE operator
+	lockUsers            string // statement which serializes registrations, empty when transactions are serialized anyway
+}
+
+// InnoDB locks all rows and the gap after them, so concurrent registrations wait for each other.
+// Only on empty table two of them can deadlock, then one of them fails and no second admin is created.
+var mysqlDialect = sqlDialect{like: "LIKE", lockUsers: "SELECT id FROM users FOR UPDATE;"}
+
+// NewMySQLRepository create repository working on given MySQL database connection
+func NewMySQLRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, dialect: mysqlDialect}
+}
+
+// rebind change ? placeholders in query to the ones used by database
+func (r *SQLRepository) rebind(query string) string {
+	if !r.dialect.numberedPlaceholders {
+		return query
+	}
+
+	var rebound strings.Builder
+	number := 0
+	for _, char := range query {
+		if char == '?' {
+			number++
+			rebound.WriteString("$" + strconv.Itoa(number))
+			continue
+		}
+		rebound.WriteRune(char)
+	}
+	return rebound.String()
+}
+
+func (r *SQLRepository) query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.Query(r.rebind(query), args...)
+}
+
+// RetrieveMovieDetail found movie details, for not existing movie or movie of other user ErrMovieNotFound is returned
+func (r *SQLRepository) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	query := "SELECT tv_series.id, tv_series.name, url, COUNT(season.id) AS seriesCount FROM tv_series LEFT JOIN season ON season.serial_id = tv_series.id WHERE tv_series.id = ? AND tv_series.user_id = ? AND tv_series.deleted_at IS NULL GROUP BY tv_series.id;"
+	rows, err := r.query(query, movieID, userID)
+	if err != nil {
+		return movie, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return movie, rows.Err()
+		}
+		return movie, ErrMovieNotFound
+	}
+	err = rows.Scan(&movie.ID, &movie.Name, &movie.URL, &movie.SeriesCount)
+	if err != nil {
+		return movie, err
+	}
+	rows.Close()
+
+	movie.Tags, err = r.retrieveTags(movieID)
+	if err != nil {
+		return movie, err
+	}
+
+	// DATETIME keeps only whole seconds, so episodes watched in the same second are ordered by position
+	query = "SELECT episode.id, season.number, episode.number, episode.date FROM episode JOIN season ON season.id = episode.season_id WHERE season.serial_id = ? AND episode.watched = 1 ORDER BY date DESC, season.number DESC, episode.number DESC LIMIT 1;"
+	rows, err = r.query(query, movieID)
+	if err != nil {
+		return movie, err
+	}
+	defer rows.Close()
+
+	// movie without watched episode has no last watched one
+	if rows.Next() {
+		err = rows.Scan(&movie.LastWatchedEpisode.ID, &movie.LastWatchedEpisode.Series, &movie.LastWatchedEpisode.EpisodeNumber, &movie.DateOfLastWatchedEpisode)
+		if err != nil {
+			return movie, err
+		}
+	} else if rows.Err() != nil {
+		return movie, rows.Err()
+	}
+	rows.Close()
+
+	nextEpisodeID, err := r.nextEpisodeID(movieID, movie.LastWatchedEpisode)
+	if err != nil {
+		return movie, err
+	}
+	// movie without episodes has no watched episode, so it is never finished
+	movie.Finished = movie.LastWatchedEpisode.ID != 0 && nextEpisodeID == 0
+
+	return movie, nil
+}
+
+// nextEpisodeID return first unwatched episode placed after the last watched one, 0 when there is no such episode
+func (r *SQLRepository) nextEpisodeID(movieID int64, lastWatched models.Episode) (episodeID int64, err error) {
+	query := "SELECT episode.id FROM episode JOIN season ON season.id = episode.season_id WHERE season.serial_id = ? AND episode.watched = 0 AND (season.number > ? OR (season.number = ? AND episode.number > ?)) ORDER BY season.number, episode.number LIMIT 1;"
+	rows, err := r.query(query, movieID, lastWatched.Series, lastWatched.Series, lastWatched.EpisodeNumber)
+	if err != nil {
+		return episodeID, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return episodeID, rows.Err()
+	}
+
+	err = rows.Scan(&episodeID)
+	return episodeID, err
+}
+
+// CreateMovie function create movie of user in database
+func (r *SQLRepository) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+	tags, err := normalizeTags(payload.Tags)
+	if err != nil {
+		return movie, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return movie, err
+	}
+
+	movieID, err := r.executeStmt(
+		tx,
+		"INSERT INTO tv_series (user_id, name, url) VALUES (?, ?, ?);",
+		userID,
+		payload.MovieName,
+		payload.URL,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return movie, uniqueViolationAs(err, ErrDuplicateMovieName)
+	}
+
+	seasons := payload.SeasonsLayout()
+	err = validateSeasonsLayout(seasons)
+	if err != nil {
+		tx.Rollback()
+		return movie, err
+	}
+
+	episodes := r.newEpisodeInserter(tx)
+	for _, season := range seasons {
+		err = r.insertSeason(tx, episodes, movieID, season)
+		if err != nil {
+			tx.Rollback()
+			return movie, err
+		}
+	}
+
+	err = r.insertTags(tx, movieID, tags)
+	if err != nil {
+		tx.Rollback()
+		return movie, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return movie, err
+	}
+	return r.RetrieveMovieDetail(userID, movieID)
+}
+
+func validateSeasonsLayout(seasons []models.SeasonPayload) error {
+	episodes := 0
+	for i, season := range seasons {
+		if season.Number != i+1 || season.Episodes < 0 {
+			return ErrInvalidSeasonLayout
+		}
+		episodes += season.EpisodesCount()
+	}
+	if episodes > models.MaxEpisodesPerMovie {
+		return ErrTooManyEpisodes
+	}
+	return nil
+}
+
+// episodeInserter insert episodes in one transaction with single statement, it is prepared on first use
+// and closed together with transaction
+type episodeInserter struct {
+	tx    *sql.Tx
+	query string
+	stmt  *sql.Stmt
+}
+
+func (r *SQLRepository) newEpisodeInserter(tx *sql.Tx) *episodeInserter {
+	return &episodeInserter{
+		tx:    tx,
+		query: r.rebind("INSERT INTO episode (season_id, number, title, air_date, watched, date) VALUES (?, ?, ?, ?, 0, null);"),
+	}
+}
+
+func (e *episodeInserter) insert(seasonID int64, number int, title interface{}, airDate interface{}) error {
+	if e.stmt == nil {
+		stmt, err := e.tx.Prepare(e.query)
+		if err != nil {
+			return err
+		}
+		e.stmt = stmt
+	}
+
+	_, err := e.stmt.Exec(seasonID, number, title, airDate)
+	return err
+}
+
+func (r *SQLRepository) insertSeason(tx *sql.Tx, episodes *episodeInserter, movieID int64, season models.SeasonPayload) error {
+	seasonID, err := r.executeStmt(tx, "INSERT INTO season (serial_id, number) VALUES (?, ?)", movieID, season.Number)
+	if err != nil {
+		return err
+	}
+	return r.insertEpisodes(episodes, seasonID, 1, season)
+}
+
+func (r *SQLRepository) insertEpisodes(episodes *episodeInserter, seasonID int64, fromNumber int, season models.SeasonPayload) error {
+	for episodeNumber := fromNumber; episodeNumber <= season.EpisodesCount(); episodeNumber++ {
+		var title, airDate interface{}
+		if episodeNumber <= len(season.EpisodeDetails) {
+			details := season.EpisodeDetails[episodeNumber-1]
+			if details.Title != "" {
+				title = details.Title
+			}
+			if details.AirDate != nil {
+				airDate = details.AirDate.Time
+			}
+		}
+
+		err := episodes.insert(seasonID, episodeNumber, title, airDate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeStmt execute query in transaction and return id of inserted row (0 when nothing was inserted
+// and database does not report last insert id)
+func (r *SQLRepository) executeStmt(tx *sql.Tx, query string, args ...interface{}) (id int64, err error) {
+	if r.dialect.returningID && strings.HasPrefix(query, "INSERT") {
+		query = strings.TrimSuffix(query, ";") + " RETURNING id;"
+	}
+
+	stmt, err := tx.Prepare(r.rebind(query))
+	if err != nil {
+		return id, err
+	}
+	defer stmt.Close()
+
+	if r.dialect.returningID && strings.HasPrefix(query, "INSERT") {
+		err = stmt.QueryRow(args...).Scan(&id)
+		return id, err
+	}
+
+	rows, err := stmt.Exec(args...)
+	if err != nil {
+		return id, err
+	}
+
+	if r.dialect.returningID {
+		return id, nil
+	}
+
+	id, err = rows.LastInsertId()
+	return id, err
+}
+
+// uniqueViolationAs return duplicate when err is unique constraint violation reported by MySQL, PostgreSQL
+// or SQLite driver, other errors are returned unchanged
+func uniqueViolationAs(err error, duplicate error) error {
+	message := err.Error()
+	for _, fragment := range []string{"Error 1062", "duplicate key value violates unique constraint", "UNIQUE constraint failed"} {
+		if strings.Contains(message, fragment) {
+			return duplicate
+		}
+	}
+	return err
+}
+
+// UpdateMovie function update selected movie, movie of other user is not changed and ErrMovieNotFound is returned
+func (r *SQLRepository) UpdateMovie(userID int64, movieID int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+	if payload.HasPartialSeasonsLayout() {
+		return movie, ErrPartialSeasonsLayout
+	}
+
+	tags, err := normalizeTags(payload.Tags)
+	if err != nil {
+		return movie, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return movie, err
+	}
+
+	owned, err := r.ownsMovie(tx, userID, movieID, false)
+	if err != nil {
+		tx.Rollback()
+		return movie, err
+	}
+	if !owned {
+		tx.Rollback()
+		return movie, ErrMovieNotFound
+	}
+
+	_, err = r.executeStmt(
+		tx,
+		"UPDATE tv_series SET name = ?, url = ? WHERE id = ?;",
+		payload.MovieName,
+		payload.URL,
+		movieID,
+	)
+
+	if err != nil {
+		tx.Rollback()
+		return movie, uniqueViolationAs(err, ErrDuplicateMovieName)
+	}
+
+	if seasons := payload.SeasonsLayout(); len(seasons) > 0 {
+		err = r.updateSeasonsLayout(tx, movieID, seasons)
+		if err != nil {
+			tx.Rollback()
+			return movie, err
+		}
+	}
+
+	if payload.Tags != nil {
+		err = r.replaceTags(tx, movieID, tags)
+		if err != nil {
+			tx.Rollback()
+			return movie, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return movie, err
+	}
+	return r.RetrieveMovieDetail(userID, movieID)
+}
+
+// missingMovieOr return ErrMovieNotFound when user has no movie with movieID, otherwise err is returned.
+// It is used to tell missing movie from missing season or episode.
+func (r *SQLRepository) missingMovieOr(userID int64, movieID int64, err error) error {
+	rows, queryErr := r.query("SELECT id FROM tv_series WHERE id = ? AND user_id = ? AND deleted_at IS NULL;", movieID, userID)
+	if queryErr != nil {
+		return queryErr
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return rows.Err()
+		}
+		return ErrMovieNotFound
+	}
+	return err
+}
+
+// ownsMovie check if user has movie with movieID, movie in trash is taken into account only when withTrashed is set
+func (r *SQLRepository) ownsMovie(tx *sql.Tx, userID int64, movieID int64, withTrashed bool) (bool, error) {
+	query := "SELECT id FROM tv_series WHERE id = ? AND user_id = ? AND deleted_at IS NULL;"
+	if withTrashed {
+		query = "SELECT id FROM tv_series WHERE id = ? AND user_id = ?;"
+	}
+	rows, err := tx.Query(r.rebind(query), movieID, userID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	return rows.Next(), rows.Err()
+}
+
+type seasonLayout struct {
+	id          int64
+	number      int
+	lastEpisode int
+}
+
+// updateSeasonsLayout change seasons of movie to match given layout. Existing seasons are resized,
+// missing ones appended and trailing seasons not present in layout removed, watch state of
+// remaining episodes is kept.
+func (r *SQLRepository) updateSeasonsLayout(tx *sql.Tx, movieID int64, seasons []models.SeasonPayload) error {
+	err := validateSeasonsLayout(seasons)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.retrieveSeasonsLayout(tx, movieID)
+	if err != nil {
+		return err
+	}
+
+	for _, current := range existing {
+		if current.number <= len(seasons) {
+			continue
+		}
+		_, err = r.executeStmt(tx, "DELETE FROM episode WHERE season_id = ?;", current.id)
+		if err != nil {
+			return err
+		}
+		_, err = r.executeStmt(tx, "DELETE FROM season WHERE id = ?;", current.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	episodes := r.newEpisodeInserter(tx)
+	for _, season := range seasons {
+		current, ok := existing[season.Number]
+		if !ok {
+			err = r.insertSeason(tx, episodes, movieID, season)
+		} else if current.lastEpisode > season.EpisodesCount() {
+			_, err = r.executeStmt(tx, "DELETE FROM episode WHERE season_id = ? AND number > ?;", current.id, season.EpisodesCount())
+		} else {
+			err = r.insertEpisodes(episodes, current.id, current.lastEpisode+1, season)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLRepository) retrieveSeasonsLayout(tx *sql.Tx, movieID int64) (layout map[int]seasonLayout, err error) {
+	query := "SELECT season.id, season.number, COALESCE(MAX(episode.number), 0) FROM season LEFT JOIN episode ON episode.season_id = season.id WHERE season.serial_id = ? GROUP BY season.id, season.number;"
+	rows, err := tx.Query(r.rebind(query), movieID)
+	if err != nil {
+		return layout, err
+	}
+	defer rows.Close()
+
+	layout = map[int]seasonLayout{}
+	for rows.Next() {
+		var season seasonLayout
+
+		err = rows.Scan(&season.id, &season.number, &season.lastEpisode)
+		if err != nil {
+			return layout, err
+		}
+		layout[season.number] = season
+	}
+	return layout, rows.Err()
+}
+
+// DeleteMovie function permanently remove movie (also one in trash) with its episodes and seasons in one
+// transaction (foreign keys do not cascade), ErrMovieNotFound is returned when user has no such movie
+func (r *SQLRepository) DeleteMovie(userID int64, movieID int64) (deletion models.MovieDeletion, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return deletion, err
+	}
+
+	owned, err := r.ownsMovie(tx, userID, movieID, true)
+	if err != nil {
+		tx.Rollback()
+		return deletion, err
+	}
+	if !owned {
+		tx.Rollback()
+		return deletion, ErrMovieNotFound
+	}
+
+	deletion, err = r.deleteMovieRows(tx, movieID)
+	if err != nil {
+		tx.Rollback()
+		return models.MovieDeletion{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.MovieDeletion{}, err
+	}
+	return deletion, nil
+}
+
+// deleteMovieRows remove tags, episodes, seasons and finally movie itself, so no foreign key is violated
+func (r *SQLRepository) deleteMovieRows(tx *sql.Tx, movieID int64) (deletion models.MovieDeletion, err error) {
+	_, err = r.executeCount(tx, "DELETE FROM tag WHERE serial_id = ?;", movieID)
+	if err != nil {
+		return deletion, err
+	}
+
+	deletion.Episodes, err = r.executeCount(tx, "DELETE FROM episode WHERE season_id IN (SELECT id FROM season WHERE serial_id = ?);", movieID)
+	if err != nil {
+		return deletion, err
+	}
+
+	deletion.Seasons, err = r.executeCount(tx, "DELETE FROM season WHERE serial_id = ?;", movieID)
+	if err != nil {
+		return deletion, err
+	}
+
+	deletion.Movies, err = r.executeCount(tx, "DELETE FROM tv_series WHERE id = ?;", movieID)
+	return deletion, err
+}
+
+// executeCount execute query in transaction and return number of affected rows
+func (r *SQLRepository) executeCount(tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	result, err := tx.Exec(r.rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SetEpisodeWatched function mark selected episode as watched (with current date) or unwatched
+func (r *SQLRepository) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	episodeID, err := r.findEpisodeID(userID, movieID, seasonNumber, episodeNumber)
+	if err == ErrEpisodeNotFound {
+		return movie, r.missingMovieOr(userID, movieID, err)
+	}
+	if err != nil {
+		return movie, err
+	}
+
+	err = r.markEpisode(episodeID, watched)
+	if err != nil {
+		return movie, err
+	}
+
+	return r.RetrieveMovieDetail(userID, movieID)
+}
+
+// WatchNextEpisode function mark as watched first unwatched episode placed after the last watched one.
+// When there is no such episode returned movie has Finished flag set. Episode is marked only when it is still
+// unwatched, so concurrent requests mark following episodes instead of the same one.
+func (r *SQLRepository) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	for attempt := 0; attempt < watchNextAttempts; attempt++ {
+		movie, err = r.RetrieveMovieDetail(userID, movieID)
+		if err != nil || movie.Finished {
+			return movie, err
+		}
+
+		episodeID, err := r.nextEpisodeID(movieID, movie.LastWatchedEpisode)
+		if err != nil || episodeID == 0 {
+			return movie, err
+		}
+
+		marked, err := r.markUnwatchedEpisode(episodeID)
+		if err != nil {
+			return movie, err
+		}
+		if marked {
+			return r.RetrieveMovieDetail(userID, movieID)
+		}
+		// episode was marked by concurrent request in the meantime, so the one after it is taken
+	}
+	return movie, ErrWatchNextConflict
+}
+
+// watchNextAttempts limit how many times WatchNextEpisode looks for next episode, when concurrent
+// requests keep marking it first
+const watchNextAttempts = 3
+
+// markUnwatchedEpisode mark episode as watched only when it is not watched yet, false is returned
+// when it was already watched
+func (r *SQLRepository) markUnwatchedEpisode(episodeID int64) (marked bool, err error) {
+	result, err := r.db.Exec(r.rebind("UPDATE episode SET watched = 1, date = ? WHERE id = ? AND watched = 0;"), time.Now(), episodeID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *SQLRepository) markEpisode(episodeID int64, watched bool) (err error) {
+	query := r.rebind("UPDATE episode SET watched = ?, date = ? WHERE id = ?;")
+	if watched {
+		_, err = r.db.Exec(query, 1, time.Now(), episodeID)
+	} else {
+		_, err = r.db.Exec(query, 0, nil, episodeID)
+	}
+	return err
+}
+
+func (r *SQLRepository) findEpisodeID(userID int64, movieID int64, seasonNumber int, episodeNumber int) (episodeID int64, err error) {
+	query := "SELECT episode.id FROM episode JOIN season ON season.id = episode.season_id JOIN tv_series ON tv_series.id = season.serial_id WHERE season.serial_id = ? AND tv_series.user_id = ? AND tv_series.deleted_at IS NULL AND season.number = ? AND episode.number = ?;"
+	rows, err := r.query(query, movieID, userID, seasonNumber, episodeNumber)
+	if err != nil {
+		return episodeID, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return episodeID, rows.Err()
+		}
+		return episodeID, ErrEpisodeNotFound
+	}
+
+	err = rows.Scan(&episodeID)
+	return episodeID, err
+}
+
+// RetrieveSeasons function return all seasons of movie with number of watched episodes
+func (r *SQLRepository) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	query := "SELECT season.number, COUNT(episode.id), COALESCE(SUM(CASE WHEN episode.watched = 1 THEN 1 ELSE 0 END), 0) FROM season JOIN tv_series ON tv_series.id = season.serial_id LEFT JOIN episode ON episode.season_id = season.id WHERE season.serial_id = ? AND tv_series.user_id = ? AND tv_series.deleted_at IS NULL GROUP BY season.id, season.number ORDER BY season.number;"
+	rows, err := r.query(query, movieID, userID)
+	if err != nil {
+		return seasons, err
+	}
+	defer rows.Close()
+
+	seasons = models.Seasons{}
+	for rows.Next() {
+		var season models.Season
+
+		err = rows.Scan(&season.Number, &season.EpisodesCount, &season.WatchedCount)
+		if err != nil {
+			return seasons, err
+		}
+		if season.EpisodesCount > 0 {
+			season.Progress = float64(season.WatchedCount) * 100 / float64(season.EpisodesCount)
+		}
+		seasons = append(seasons, season)
+	}
+	if len(seasons) == 0 {
+		return seasons, r.missingMovieOr(userID, movieID, rows.Err())
+	}
+	return seasons, rows.Err()
+}
+
+// RetrieveEpisodes function return watch state of all episodes in selected season
+func (r *SQLRepository) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	seasonID, err := r.findSeasonID(userID, movieID, seasonNumber)
+	if err == ErrSeasonNotFound {
+		return episodes, r.missingMovieOr(userID, movieID, err)
+	}
+	if err != nil {
+		return episodes, err
+	}
+
+	query := "SELECT number, COALESCE(title, ''), air_date, watched, date FROM episode WHERE season_id = ? ORDER BY number;"
+	rows, err := r.query(query, seasonID)
+	if err != nil {
+		return episodes, err
+	}
+	defer rows.Close()
+
+	episodes = models.EpisodeStates{}
+	for rows.Next() {
+		var episode models.EpisodeState
+		var airDate *time.Time
+
+		err = rows.Scan(&episode.Number, &episode.Title, &airDate, &episode.Watched, &episode.Date)
+		if err != nil {
+			return episodes, err
+		}
+		if airDate != nil {
+			date := models.NewDate(*airDate)
+			episode.AirDate = &date
+		}
+		episodes = append(episodes, episode)
+	}
+	return episodes, rows.Err()
+}
+
+func (r *SQLRepository) findSeasonID(userID int64, movieID int64, seasonNumber int) (seasonID int64, err error) {
+	query := "SELECT season.id FROM season JOIN tv_series ON tv_series.id = season.serial_id WHERE season.serial_id = ? AND tv_series.user_id = ? AND tv_series.deleted_at IS NULL AND season.number = ?;"
+	rows, err := r.query(query, movieID, userID, seasonNumber)
+	if err != nil {
+		return seasonID, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return seasonID, rows.Err()
+		}
+		return seasonID, ErrSeasonNotFound
+	}
+
+	err = rows.Scan(&seasonID)
+	return seasonID, err
+}
@@ -0,0 +1,1480 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const testUserID int64 = 5
+
+type movieTestInternalsData struct {
+	movieListRows          *sqlmock.Rows
+	movieDetailRow         *sqlmock.Rows
+	movieDetailLastWatched *sqlmock.Rows
+}
+
+func setupInternals(t *testing.T) (*SQLRepository, sqlmock.Sqlmock, movieTestInternalsData) {
+	var testData movieTestInternalsData
+	date, _ := time.Parse(time.RFC822Z, "2017-01-02 18:42:20")
+
+	testData.movieListRows = sqlmock.NewRows([]string{"id", "name", "url", "episodes", "watched"}).
+		AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 4, 1).
+		AddRow(2, "Test Movie 2", "http://www.example.com/movie2", 0, 0)
+
+	testData.movieDetailRow = sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}).
+		AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 5)
+	testData.movieDetailLastWatched = sqlmock.NewRows([]string{"id", "id", "number", "date"}).
+		AddRow(1, 1, 4, date)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	return NewMySQLRepository(db), mock, testData
+}
+
+// expectNextEpisode expect query for the first unwatched episode after the last watched one, it is read
+// with movie details to tell if movie is finished
+func expectNextEpisode(mock sqlmock.Sqlmock, lastSeries int, lastEpisode int, nextEpisodeID int64) {
+	mock.ExpectQuery("SELECT episode.id FROM episode (.+) ORDER BY season.number, episode.number LIMIT 1;").
+		WithArgs(1, lastSeries, lastSeries, lastEpisode).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(nextEpisodeID))
+}
+
+// expectMovieTags expect query for tags of movie read with its details
+func expectMovieTags(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT name FROM tag WHERE serial_id = (.+) ORDER BY name;").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("drama"))
+}
+func TestRetriveMovieItems(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series.id, (.+) FROM tv_series LEFT JOIN (.+) progress (.+) WHERE tv_series.user_id = (.+) AND tv_series.name LIKE (.+) ORDER BY tv_series.id ASC, tv_series.id ASC LIMIT (.+) OFFSET (.+);").
+		WithArgs(testUserID, "Test", 10, 0).
+		WillReturnRows(testData.movieListRows)
+
+	movies, err := repository.RetrieveMovieItems(testUserID, models.MovieListQuery{SearchString: "Test", Limit: 10})
+
+	if err != nil {
+		t.Errorf("Can no retrive movie items, got error: %s", err)
+	}
+
+	if len(movies) != 2 {
+		t.Fatalf("Wrong number of movies, expected 2, got %d", len(movies))
+	}
+
+	if movies[0].Progress != 25 || movies[1].Progress != 0 {
+		t.Errorf("Wrong progress, expected 25 and 0, got %v and %v", movies[0].Progress, movies[1].Progress)
+	}
+}
+
+func TestRetriveMovieItemsFilteredAndSorted(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+	since := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("WHERE tv_series.user_id = (.+) AND tv_series.deleted_at IS NULL AND progress.watched > 0 AND progress.watched < progress.episodes AND progress.last_watched >= (.+) AND EXISTS (.+) ORDER BY progress.last_watched IS NULL, progress.last_watched DESC, tv_series.id DESC LIMIT (.+) OFFSET (.+);").
+		WithArgs(testUserID, since, "drama", 10, 0).
+		WillReturnRows(testData.movieListRows)
+
+	_, err := repository.RetrieveMovieItems(testUserID, models.MovieListQuery{
+		Status:       models.MovieStatusInProgress,
+		WatchedSince: &since,
+		Tag:          " Drama",
+		Sort:         models.MovieSortLastWatched,
+		Order:        "desc",
+		Limit:        10,
+	})
+	if err != nil {
+		t.Errorf("Can no retrive movie items, got error: %s", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRetriveMovieItemsUnknownSort(t *testing.T) {
+	repository, _, _ := setupInternals(t)
+
+	for _, query := range []models.MovieListQuery{{Sort: "id; DROP TABLE tag"}, {Order: "up"}, {Status: "paused"}} {
+		_, err := repository.RetrieveMovieItems(testUserID, query)
+		if err != ErrInvalidMovieListQuery {
+			t.Errorf("Wrong error for %+v, expected '%s', got '%v'", query, ErrInvalidMovieListQuery, err)
+		}
+	}
+}
+
+func TestRetriveMovieItemsError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT id, name, url FROM tv_series WHERE user_id = (.+) AND name LIKE (.+) LIMIT (.+) OFFSET (.+);").
+		WithArgs(testUserID, "Test", 10, 0).
+		WillReturnError(fmt.Errorf("Test Error"))
+
+	movies, err := repository.RetrieveMovieItems(testUserID, models.MovieListQuery{SearchString: "Test", Limit: 10})
+
+	if err == nil {
+		t.Errorf("Function does not return error")
+	}
+
+	if len(movies) != 0 {
+		t.Errorf("Wrong number of movies, expected 0, got %d", len(movies))
+	}
+}
+
+func TestExecuteStmtPrepareError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("(.)+").
+		WillReturnError(fmt.Errorf("Test error in prepare"))
+
+	tx, _ := repository.db.Begin()
+
+	id, err := repository.executeStmt(tx, "SELECT * FROM test")
+
+	if id != 0 {
+		t.Errorf("Wrong id, expected 0, got %d", id)
+	}
+
+	if err.Error() != "Test error in prepare" {
+		t.Errorf("Wrong error message, expected 'Test error in prepare', got %s", err)
+	}
+}
+
+func TestExecuteStmtExecError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("(.+)")
+	mock.ExpectExec("(.+)").
+		WillReturnError(fmt.Errorf("Test error in execute"))
+
+	tx, _ := repository.db.Begin()
+
+	id, err := repository.executeStmt(tx, "SELECT * FROM test")
+
+	if id != 0 {
+		t.Errorf("Wrong id, expected 0, got %d", id)
+	}
+
+	if err.Error() != "Test error in execute" {
+		t.Errorf("Wrong error message, expected 'Test error in execute', got %s", err)
+	}
+}
+
+func TestExecuteStmt(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("(.+)")
+	mock.ExpectExec("(.+)").
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	tx, _ := repository.db.Begin()
+
+	id, err := repository.executeStmt(tx, "SELECT * FROM test")
+
+	if id != 1 {
+		t.Errorf("Wrong id, expected 1, got %d", id)
+	}
+
+	if err != nil {
+		t.Errorf("Wrong error message, expected 'nil', got %s", err)
+	}
+}
+
+func TestCreateMovieCommitFail(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("Test error during commit"))
+
+	payload := models.MovieCreationPayload{MovieName: "Test movie", SeriesNumber: 1, EpisodesInSeries: 1}
+	_, err := repository.CreateMovie(testUserID, payload)
+
+	if err == nil || err.Error() != "Test error during commit" {
+		t.Errorf("Expected error 'Test error during commit', got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Movie details should not be read after failed commit, %s", err)
+	}
+}
+
+func TestCreateMovie(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectBegin()
+	// Create movie
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Create season 1
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Create episode
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 1, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Create season 2
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	// Create episode
+	mock.ExpectExec("(.)+").
+		WithArgs(2, 1, nil, nil).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     2,
+		EpisodesInSeries: 1,
+	}
+
+	movieDetail, err := repository.CreateMovie(testUserID, payload)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if movieDetail.ID != 1 {
+		t.Errorf("Wrong ID, expected 1, got %d", movieDetail.ID)
+	}
+
+	if movieDetail.Name != "Test Movie 1" {
+		t.Errorf("Wrong movie name, expected 'Test Movie 1', got %s", movieDetail.Name)
+	}
+
+	if movieDetail.URL != "http://www.example.com/movie1" {
+		t.Errorf("Wrong movie url, expected 'http://www.example.com/movie1', got %s", movieDetail.URL)
+	}
+
+	if movieDetail.SeriesCount != 5 {
+		t.Errorf("Wrong movie series count, expected 5, got %d", movieDetail.SeriesCount)
+	}
+}
+
+func TestCreateMovieFailTransaction(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("Transaction start error"))
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     2,
+		EpisodesInSeries: 1,
+	}
+
+	movieDetail, err := repository.CreateMovie(testUserID, payload)
+
+	if err.Error() != "Transaction start error" {
+		t.Errorf("Wrong error, expected 'Transaction start error', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Error("Movie was created when error occure")
+	}
+}
+
+func TestCreateMovieFailCreateSeries(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnError(fmt.Errorf("Test error durring create tv_series"))
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     2,
+		EpisodesInSeries: 1,
+	}
+
+	movieDetail, err := repository.CreateMovie(testUserID, payload)
+
+	if err.Error() != "Test error durring create tv_series" {
+		t.Errorf("Wrong error, expected 'Test error durring create tv_series', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Error("Movie was created when error occure")
+	}
+}
+
+func TestCreateMovieFailCreateSeason(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnError(fmt.Errorf("Test error durring create season"))
+	mock.ExpectRollback()
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     2,
+		EpisodesInSeries: 1,
+	}
+
+	movieDetail, err := repository.CreateMovie(testUserID, payload)
+
+	if err.Error() != "Test error durring create season" {
+		t.Errorf("Wrong error, expected 'Test error durring create season', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Error("Movie was created when error occure")
+	}
+}
+
+func TestCreateMovieTooManyEpisodes(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	_, err := repository.CreateMovie(testUserID, models.MovieCreationPayload{MovieName: "Test movie", SeriesNumber: 11, EpisodesInSeries: 500})
+	if err != ErrTooManyEpisodes {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrTooManyEpisodes, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestCreateMovieFailCreateEpisode(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	// Create movie
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Create season 1
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// Create episode
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 1, nil, nil).
+		WillReturnError(fmt.Errorf("Test error during create episode"))
+
+	mock.ExpectRollback()
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     2,
+		EpisodesInSeries: 1,
+	}
+
+	movieDetail, err := repository.CreateMovie(testUserID, payload)
+
+	if err.Error() != "Test error during create episode" {
+		t.Errorf("Wrong error, expected 'Test error during create episode', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Error("Movie was created when error occure")
+	}
+}
+
+func TestUpdateMovie(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	mock.ExpectPrepare("UPDATE tv_series SET (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+	}
+
+	movieDetail, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if movieDetail.ID != 1 {
+		t.Errorf("Wrong ID, expected 1, got %d", movieDetail.ID)
+	}
+
+	if movieDetail.Name != "Test Movie 1" {
+		t.Errorf("Wrong movie name, expected 'Test Movie 1', got %s", movieDetail.Name)
+	}
+
+	if movieDetail.URL != "http://www.example.com/movie1" {
+		t.Errorf("Wrong movie url, expected 'http://www.example.com/movie1', got %s", movieDetail.URL)
+	}
+
+	if movieDetail.SeriesCount != 5 {
+		t.Errorf("Wrong movie series count, expected 5, got %d", movieDetail.SeriesCount)
+	}
+}
+
+func TestUpdateMovieFailBeginTransaction(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("Test error during begin"))
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+	}
+
+	movieDetail, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err.Error() != "Test error during begin" {
+		t.Errorf("Expected error 'Test error during begin', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Errorf("Wrong ID, expected 0, got %d", movieDetail.ID)
+	}
+}
+
+func TestUpdateMovieFailExecuteUpdate(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	mock.ExpectPrepare("UPDATE tv_series SET (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnError(fmt.Errorf("Test error during update"))
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+	}
+
+	movieDetail, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err.Error() != "Test error during update" {
+		t.Errorf("Expected error 'Test error during update', got %s", err)
+	}
+
+	if movieDetail.ID != 0 {
+		t.Errorf("Wrong ID, expected 0, got %d", movieDetail.ID)
+	}
+}
+
+func TestRetrieveMovieDetail(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode(.+) ORDER BY date DESC, season.number DESC, episode.number DESC LIMIT 1;").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	movie, err := repository.RetrieveMovieDetail(testUserID, 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if movie.ID != 1 {
+		t.Errorf("Wrong movie ID, expected 1, got %d", movie.ID)
+	}
+
+	if movie.Finished {
+		t.Error("Movie is finished, expected not finished")
+	}
+
+	if len(movie.Tags) != 1 || movie.Tags[0] != "drama" {
+		t.Errorf("Wrong tags, expected drama, got %v", movie.Tags)
+	}
+}
+
+func TestRetrieveMovieDetailNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(999, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}))
+
+	movie, err := repository.RetrieveMovieDetail(testUserID, 999)
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+
+	if movie.ID != 0 {
+		t.Errorf("Wrong movie ID, expected 0, got %d", movie.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestRetrieveMovieDetailFailTVSeriesQuery(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnError(fmt.Errorf("Test error during tv_series"))
+
+	movie, err := repository.RetrieveMovieDetail(testUserID, 1)
+
+	if err.Error() != "Test error during tv_series" {
+		t.Errorf("Expected error 'Test error during tv_series', got: %s", err)
+	}
+
+	if movie.ID != 0 {
+		t.Errorf("Wrong movie ID, expected 0, got %d", movie.ID)
+	}
+}
+
+func TestRetrieveMovieDetailFailEpisodeQuery(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode(.+)").
+		WithArgs(1).
+		WillReturnError(fmt.Errorf("Test error during episode"))
+
+	_, err := repository.RetrieveMovieDetail(testUserID, 1)
+
+	if err == nil || err.Error() != "Test error during episode" {
+		t.Errorf("Expected error 'Test error during episode', got: %v", err)
+	}
+}
+
+func TestRetrieveMovieDetailFailEpisodeScan(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "number", "date"}).
+			AddRow(1, 1, 4, "not a date"))
+
+	_, err := repository.RetrieveMovieDetail(testUserID, 1)
+
+	if err == nil || !strings.Contains(err.Error(), "Scan error") {
+		t.Errorf("Expected scan error, got %v", err)
+	}
+}
+
+func TestDeleteMovie(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM tag WHERE serial_id = (.+);").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM episode WHERE season_id IN (.+)").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 18))
+	mock.ExpectExec("DELETE FROM season WHERE serial_id = (.+);").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM tv_series WHERE id = (.+);").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deletion, err := repository.DeleteMovie(testUserID, 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if deletion.Movies != 1 || deletion.Seasons != 2 || deletion.Episodes != 18 {
+		t.Errorf("Wrong number of removed rows, expected 1 movie, 2 seasons and 18 episodes, got %v", deletion)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestDeleteMovieNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err := repository.DeleteMovie(testUserID, 1)
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestDeleteMovieFailedBeginTransaction(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("Test error during begin"))
+
+	_, err := repository.DeleteMovie(testUserID, 1)
+
+	if err.Error() != "Test error during begin" {
+		t.Errorf("Expected error 'Test error during begin', got %s", err)
+	}
+}
+
+func TestDeleteMovieFailedExecute(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM tag WHERE serial_id = (.+);").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM episode WHERE season_id IN (.+)").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 18))
+	mock.ExpectExec("DELETE FROM season WHERE serial_id = (.+);").
+		WithArgs(1).
+		WillReturnError(fmt.Errorf("Test error in execute"))
+	mock.ExpectRollback()
+
+	deletion, err := repository.DeleteMovie(testUserID, 1)
+
+	if err.Error() != "Test error in execute" {
+		t.Errorf("Expected error 'Test error in execute', got %s", err)
+	}
+
+	if deletion.Episodes != 0 {
+		t.Errorf("Rolled back deletion should not report removed rows, got %v", deletion)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestSetEpisodeWatched(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode JOIN season (.+)").
+		WithArgs(1, testUserID, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(17))
+
+	mock.ExpectExec("UPDATE episode SET watched (.+)").
+		WithArgs(1, sqlmock.AnyArg(), 17).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	movie, err := repository.SetEpisodeWatched(testUserID, 1, 2, 3, true)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if movie.ID != 1 {
+		t.Errorf("Wrong movie ID, expected 1, got %d", movie.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestSetEpisodeUnwatched(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode JOIN season (.+)").
+		WithArgs(1, testUserID, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(17))
+
+	mock.ExpectExec("UPDATE episode SET watched (.+)").
+		WithArgs(0, nil, 17).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+
+	mock.ExpectQuery("SELECT episode(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	_, err := repository.SetEpisodeWatched(testUserID, 1, 2, 3, false)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestSetEpisodeWatchedNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode JOIN season (.+)").
+		WithArgs(1, testUserID, 2, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err := repository.SetEpisodeWatched(testUserID, 1, 2, 30, true)
+
+	if err != ErrEpisodeNotFound {
+		t.Errorf("Expected ErrEpisodeNotFound, got %v", err)
+	}
+}
+
+func TestSetEpisodeWatchedMovieNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode JOIN season (.+)").
+		WithArgs(1, testUserID, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repository.SetEpisodeWatched(testUserID, 1, 2, 3, true)
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+}
+
+func TestSetEpisodeWatchedRowError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode (.+)").
+		WithArgs(1, testUserID, 1, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).RowError(0, fmt.Errorf("Test error during episode")))
+
+	_, err := repository.SetEpisodeWatched(testUserID, 1, 1, 5, true)
+
+	if err == nil || err.Error() != "Test error during episode" {
+		t.Errorf("Expected error 'Test error during episode', got %v", err)
+	}
+}
+
+func TestSetEpisodeWatchedFailedExecute(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode JOIN season (.+)").
+		WithArgs(1, testUserID, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(17))
+
+	mock.ExpectExec("UPDATE episode SET watched (.+)").
+		WillReturnError(fmt.Errorf("Test error in execute"))
+
+	_, err := repository.SetEpisodeWatched(testUserID, 1, 2, 3, true)
+
+	if err.Error() != "Test error in execute" {
+		t.Errorf("Expected error 'Test error in execute', got %s", err)
+	}
+}
+
+func TestWatchNextEpisode(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode (.+) ORDER BY season.number, episode.number LIMIT 1;").
+		WithArgs(1, 1, 1, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	mock.ExpectExec("UPDATE episode SET watched = 1, date = (.+) WHERE id = (.+) AND watched = 0;").
+		WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}).
+			AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 5))
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "number", "date"}).
+			AddRow(5, 1, 5, time.Now()))
+	expectNextEpisode(mock, 1, 5, 6)
+
+	movie, err := repository.WatchNextEpisode(testUserID, 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if movie.LastWatchedEpisode.EpisodeNumber != 5 {
+		t.Errorf("Wrong last watched episode, expected 5, got %d", movie.LastWatchedEpisode.EpisodeNumber)
+	}
+
+	if movie.Finished {
+		t.Error("Movie is finished, expected not finished")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestWatchNextEpisodeMarkedConcurrently(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+	expectNextEpisode(mock, 1, 4, 5)
+	// other request marked episode 5 first
+	mock.ExpectExec("UPDATE episode SET watched = 1, date = (.+) WHERE id = (.+) AND watched = 0;").
+		WithArgs(sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// details are read again, now with episode 5 watched
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}).
+			AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 5))
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "number", "date"}).
+			AddRow(5, 1, 5, time.Now()))
+	expectNextEpisode(mock, 1, 5, 6)
+	expectNextEpisode(mock, 1, 5, 6)
+	mock.ExpectExec("UPDATE episode SET watched = 1, date = (.+) WHERE id = (.+) AND watched = 0;").
+		WithArgs(sqlmock.AnyArg(), 6).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}).
+			AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 5))
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "number", "date"}).
+			AddRow(6, 1, 6, time.Now()))
+	expectNextEpisode(mock, 1, 6, 7)
+
+	movie, err := repository.WatchNextEpisode(testUserID, 1)
+
+	if err != nil || movie.LastWatchedEpisode.EpisodeNumber != 6 {
+		t.Errorf("Episode after the concurrently marked one should be watched, got %v, %v", movie.LastWatchedEpisode, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestWatchNextEpisodeFinished(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode (.+) ORDER BY season.number, episode.number LIMIT 1;").
+		WithArgs(1, 1, 1, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	movie, err := repository.WatchNextEpisode(testUserID, 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !movie.Finished {
+		t.Error("Movie is not finished, expected finished")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestWatchNextEpisodeFailedQuery(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectQuery("SELECT tv_series(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number(.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+
+	mock.ExpectQuery("SELECT episode.id FROM episode (.+)").
+		WillReturnError(fmt.Errorf("Test error during next episode"))
+
+	_, err := repository.WatchNextEpisode(testUserID, 1)
+
+	if err.Error() != "Test error during next episode" {
+		t.Errorf("Expected error 'Test error during next episode', got %s", err)
+	}
+}
+
+func TestRetrieveSeasons(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.number, COUNT(.+) FROM season JOIN tv_series (.+) LEFT JOIN episode (.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "episodes", "watched"}).
+			AddRow(1, 10, 10).
+			AddRow(2, 8, 2).
+			AddRow(3, 0, 0))
+
+	seasons, err := repository.RetrieveSeasons(testUserID, 1)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if len(seasons) != 3 {
+		t.Fatalf("Wrong number of seasons, expected 3, got %d", len(seasons))
+	}
+
+	if seasons[0].Progress != 100 {
+		t.Errorf("Wrong progress of season 1, expected 100, got %f", seasons[0].Progress)
+	}
+
+	if seasons[1].Progress != 25 {
+		t.Errorf("Wrong progress of season 2, expected 25, got %f", seasons[1].Progress)
+	}
+
+	if seasons[2].Progress != 0 {
+		t.Errorf("Wrong progress of season 3, expected 0, got %f", seasons[2].Progress)
+	}
+}
+
+func TestRetrieveSeasonsError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.number(.+)").
+		WithArgs(1, testUserID).
+		WillReturnError(fmt.Errorf("Test error during seasons"))
+
+	_, err := repository.RetrieveSeasons(testUserID, 1)
+
+	if err.Error() != "Test error during seasons" {
+		t.Errorf("Expected error 'Test error during seasons', got %s", err)
+	}
+}
+
+func TestRetrieveSeasonsMovieNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.number(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "episodes", "watched"}))
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repository.RetrieveSeasons(testUserID, 1)
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+}
+
+func TestRetrieveEpisodes(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+	date := time.Now()
+
+	mock.ExpectQuery("SELECT season.id FROM season JOIN tv_series (.+)").
+		WithArgs(1, testUserID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	mock.ExpectQuery("SELECT number, COALESCE(.+), air_date, watched, date FROM episode (.+)").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "title", "air_date", "watched", "date"}).
+			AddRow(1, "Pilot", date, "1", date).
+			AddRow(2, "", nil, "0", nil))
+
+	episodes, err := repository.RetrieveEpisodes(testUserID, 1, 2)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if len(episodes) != 2 {
+		t.Fatalf("Wrong number of episodes, expected 2, got %d", len(episodes))
+	}
+
+	if !episodes[0].Watched || episodes[0].Date == nil {
+		t.Errorf("Wrong state of first episode, expected watched with date, got %v", episodes[0])
+	}
+
+	if episodes[0].Title != "Pilot" {
+		t.Errorf("Wrong title of first episode, expected 'Pilot', got %s", episodes[0].Title)
+	}
+
+	if episodes[0].AirDate == nil || episodes[0].AirDate.Format(models.DateLayout) != date.Format(models.DateLayout) {
+		t.Errorf("Wrong air date of first episode, expected %s, got %v", date.Format(models.DateLayout), episodes[0].AirDate)
+	}
+
+	if episodes[1].Watched || episodes[1].Date != nil || episodes[1].AirDate != nil {
+		t.Errorf("Wrong state of second episode, expected unwatched without dates, got %v", episodes[1])
+	}
+}
+
+func TestRetrieveEpisodesSeasonNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.id FROM season JOIN tv_series (.+)").
+		WithArgs(1, testUserID, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err := repository.RetrieveEpisodes(testUserID, 1, 9)
+
+	if err != ErrSeasonNotFound {
+		t.Errorf("Expected ErrSeasonNotFound, got %v", err)
+	}
+}
+
+func TestRetrieveEpisodesRowError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.id FROM season JOIN tv_series (.+)").
+		WithArgs(1, testUserID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery("SELECT number, COALESCE(.+), air_date, watched, date FROM episode (.+)").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"number", "title", "air_date", "watched", "date"}).
+			AddRow(1, "Pilot", nil, "0", nil).
+			AddRow(2, "", nil, "0", nil).
+			RowError(1, fmt.Errorf("Test error during episode")))
+
+	_, err := repository.RetrieveEpisodes(testUserID, 1, 2)
+
+	if err == nil || err.Error() != "Test error during episode" {
+		t.Errorf("Expected error 'Test error during episode', got %v", err)
+	}
+}
+
+func TestRetrieveEpisodesSeasonRowError(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.id FROM season JOIN tv_series (.+)").
+		WithArgs(1, testUserID, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7).RowError(0, fmt.Errorf("Test error during season")))
+
+	_, err := repository.RetrieveEpisodes(testUserID, 1, 2)
+
+	if err == nil || err.Error() != "Test error during season" {
+		t.Errorf("Expected error 'Test error during season', got %v", err)
+	}
+}
+
+func TestRetrieveEpisodesMovieNotFound(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectQuery("SELECT season.id FROM season JOIN tv_series (.+)").
+		WithArgs(1, testUserID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repository.RetrieveEpisodes(testUserID, 1, 1)
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+}
+
+func TestUpdateMovieSeasonsLayout(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	mock.ExpectPrepare("UPDATE tv_series SET (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectQuery("SELECT season.id, season.number, COALESCE(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "lastEpisode"}).
+			AddRow(11, 1, 3).
+			AddRow(12, 2, 2).
+			AddRow(13, 3, 2))
+
+	// Remove trailing season 3
+	mock.ExpectPrepare("DELETE FROM episode WHERE season_id = (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(13).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare("DELETE FROM season WHERE id = (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(13).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Shrink season 1
+	mock.ExpectPrepare("DELETE FROM episode WHERE season_id = (.+) AND number > (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(11, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Extend season 2
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(12, 3, nil, nil).
+		WillReturnResult(sqlmock.NewResult(20, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number, episode.number, episode.date (.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+		Seasons: []models.SeasonPayload{
+			{Number: 1, Episodes: 2},
+			{Number: 2, Episodes: 3},
+		},
+	}
+
+	_, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestUpdateMovieAppendSeason(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	mock.ExpectPrepare("UPDATE tv_series SET (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectQuery("SELECT season.id, season.number, COALESCE(.+)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number", "lastEpisode"}).
+			AddRow(11, 1, 1))
+
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(12, 1, nil, nil).
+		WillReturnResult(sqlmock.NewResult(20, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number, episode.number, episode.date (.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+		Seasons: []models.SeasonPayload{
+			{Number: 1, Episodes: 1},
+			{Number: 2, Episodes: 1},
+		},
+	}
+
+	_, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestUpdateMovieInvalidSeasonsLayout(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	mock.ExpectPrepare("UPDATE tv_series SET (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs("Test movie", "http://www.example.com", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	mock.ExpectRollback()
+
+	payload := models.MovieUpdatePayload{
+		MovieName: "Test movie",
+		URL:       "http://www.example.com",
+		Seasons: []models.SeasonPayload{
+			{Number: 1, Episodes: 1},
+			{Number: 3, Episodes: 1},
+		},
+	}
+
+	_, err := repository.UpdateMovie(testUserID, 1, payload)
+
+	if err != ErrInvalidSeasonLayout {
+		t.Errorf("Expected ErrInvalidSeasonLayout, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestCreateMovieWithSeasonsLayout(t *testing.T) {
+	repository, mock, testData := setupInternals(t)
+	airDate := models.NewDate(time.Date(2017, 11, 21, 0, 0, 0, 0, time.UTC))
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Season 1 with two described episodes
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectPrepare("INSERT INTO episode (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 1, "Pilot", airDate.Time).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 2, "Second", nil).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	// Season 2 with one episode
+	mock.ExpectPrepare("INSERT INTO season (.+)")
+	mock.ExpectExec("(.)+").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("(.)+").
+		WithArgs(2, 1, nil, nil).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	mock.ExpectCommit()
+
+	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
+		WithArgs(1, testUserID).
+		WillReturnRows(testData.movieDetailRow)
+	expectMovieTags(mock)
+	mock.ExpectQuery("SELECT episode.id, season.number, episode.number, episode.date (.+)").
+		WithArgs(1).
+		WillReturnRows(testData.movieDetailLastWatched)
+	expectNextEpisode(mock, 1, 4, 5)
+
+	payload := models.MovieCreationPayload{
+		MovieName:        "Test movie",
+		URL:              "http://www.example.com",
+		SeriesNumber:     5,
+		EpisodesInSeries: 5,
+		Seasons: []models.SeasonPayload{
+			{Number: 1, EpisodeDetails: []models.EpisodePayload{{Title: "Pilot", AirDate: &airDate}, {Title: "Second"}}},
+			{Number: 2, Episodes: 1},
+		},
+	}
+
+	_, err := repository.CreateMovie(testUserID, payload)
+
+	if err != nil {
+		t.Errorf("Unexpected error, got %s", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestCreateMovieInvalidSeasonsLayout(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO tv_series (.+)")
+	mock.ExpectExec("(.)+").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	payload := models.MovieCreationPayload{
+		MovieName: "Test movie",
+		Seasons:   []models.SeasonPayload{{Number: 2, Episodes: 1}},
+	}
+
+	_, err := repository.CreateMovie(testUserID, payload)
+
+	if err != ErrInvalidSeasonLayout {
+		t.Errorf("Expected ErrInvalidSeasonLayout, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
+
+func TestUpdateMovieOfOtherUser(t *testing.T) {
+	repository, mock, _ := setupInternals(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM tv_series WHERE id = (.+) AND user_id = (.+);").
+		WithArgs(1, testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	movie, err := repository.UpdateMovie(testUserID, 1, models.MovieUpdatePayload{MovieName: "Test movie"})
+
+	if err != ErrMovieNotFound {
+		t.Errorf("Expected ErrMovieNotFound, got %v", err)
+	}
+
+	if movie.ID != 0 {
+		t.Errorf("Wrong movie ID, expected 0, got %d", movie.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Not all expectations were met: %s", err)
+	}
+}
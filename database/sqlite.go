@@ -0,0 +1,14 @@
+package database
+
+import (
+	"database/sql"
+)
+
+// SQLite connection pool has only one connection, so registrations do not need additional lock
+var sqliteDialect = sqlDialect{like: "LIKE"}
+
+// NewSQLiteRepository create repository working on given SQLite database connection.
+// SQLite allows only one writer at once, so connection pool should be limited to one connection by caller.
+func NewSQLiteRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db, dialect: sqliteDialect}
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/migrations"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	_ "modernc.org/sqlite"
+)
+
+func setupSQLite(t *testing.T) *SQLRepository {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening sqlite database", err)
+	}
+	// every connection to :memory: has its own database
+	db.SetMaxOpenConns(1)
+
+	repository := NewSQLiteRepository(db)
+	migrator, err := migrations.NewMigrator(db, "sqlite")
+	if err == nil {
+		_, err = migrator.Up()
+	}
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when migrating sqlite database", err)
+	}
+
+	return repository
+}
+
+func TestSQLiteCreateAndWatchMovie(t *testing.T) {
+	testCreateAndWatchMovie(t, setupSQLite(t))
+}
+
+func TestSQLiteFinishedMovie(t *testing.T) {
+	testFinishedMovie(t, setupSQLite(t))
+}
+
+func TestSQLiteUpdateSeasonsLayout(t *testing.T) {
+	testUpdateSeasonsLayout(t, setupSQLite(t))
+}
+
+func TestSQLiteRetrieveMovieItems(t *testing.T) {
+	testRetrieveMovieItems(t, setupSQLite(t))
+}
+
+func TestSQLiteMovieListQuery(t *testing.T) {
+	testMovieListQuery(t, setupSQLite(t))
+}
+
+func TestSQLiteMovieTags(t *testing.T) {
+	testMovieTags(t, setupSQLite(t))
+}
+
+func TestSQLiteUserScoping(t *testing.T) {
+	testUserScoping(t, setupSQLite(t))
+}
+
+func TestSQLiteDeleteMovie(t *testing.T) {
+	repository := setupSQLite(t)
+	testDeleteMovie(t, repository)
+
+	// only the movie created again after deletion is left, with one season and one episode
+	for _, table := range []string{"tv_series", "season", "episode"} {
+		var count int
+		err := repository.db.QueryRow("SELECT COUNT(*) FROM " + table + ";").Scan(&count)
+		if err != nil || count != 1 {
+			t.Errorf("Expected 1 row in %s, got %d, %v", table, count, err)
+		}
+	}
+}
+
+func TestSQLiteTrash(t *testing.T) {
+	testTrash(t, setupSQLite(t))
+}
+
+func TestSQLiteTrashFreesName(t *testing.T) {
+	testTrashFreesName(t, setupSQLite(t))
+}
+
+func TestSQLiteNotExistingMovie(t *testing.T) {
+	testMovieNotFound(t, setupSQLite(t))
+}
+
+func TestSQLiteUsers(t *testing.T) {
+	testUsers(t, setupSQLite(t))
+}
+
+func TestSQLiteFirstUserAdoptsOrphanedMovies(t *testing.T) {
+	repository := setupSQLite(t)
+
+	_, err := repository.db.Exec("INSERT INTO tv_series (name, url) VALUES ('Arrow', '')")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	johnID := createTestUser(t, repository, "john")
+	janeID := createTestUser(t, repository, "jane")
+
+	movies, _ := repository.RetrieveMovieItems(johnID, models.MovieListQuery{SearchString: "%", Limit: 10})
+	if len(movies) != 1 || movies[0].Name != "Arrow" {
+		t.Errorf("First user does not own orphaned movie, got %v", movies)
+	}
+
+	movies, _ = repository.RetrieveMovieItems(janeID, models.MovieListQuery{SearchString: "%", Limit: 10})
+	if len(movies) != 0 {
+		t.Errorf("Second user owns orphaned movie, got %v", movies)
+	}
+}
+
+func TestSQLiteStats(t *testing.T) {
+	testStats(t, setupSQLite(t))
+}
+
+func TestSQLiteCreateMovieDuplicatedName(t *testing.T) {
+	repository := setupSQLite(t)
+	userID := createTestUser(t, repository, "john")
+	repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+
+	_, err := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Arrow"})
+	if err != ErrDuplicateMovieName {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrDuplicateMovieName, err)
+	}
+
+	flash, _ := repository.CreateMovie(userID, models.MovieCreationPayload{MovieName: "Flash"})
+	_, err = repository.UpdateMovie(userID, flash.ID, models.MovieUpdatePayload{MovieName: "Arrow"})
+	if err != ErrDuplicateMovieName {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrDuplicateMovieName, err)
+	}
+}
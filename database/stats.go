@@ -0,0 +1,18 @@
+package database
+
+import (
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// RetrieveStats count users, shows and episodes of all users, EpisodesWatchedSince counts episodes
+// watched not earlier than since. Shows in trash and their episodes are not counted.
+func (r *SQLRepository) RetrieveStats(since time.Time) (stats models.Stats, err error) {
+	episodes := "SELECT COUNT(*) FROM episode JOIN season ON season.id = episode.season_id JOIN tv_series ON tv_series.id = season.serial_id WHERE tv_series.deleted_at IS NULL"
+	query := "SELECT (SELECT COUNT(*) FROM users), (SELECT COUNT(*) FROM tv_series WHERE deleted_at IS NULL), (" + episodes + "), " +
+		"(" + episodes + " AND episode.watched = 1), (" + episodes + " AND episode.watched = 1 AND episode.date >= ?);"
+	err = r.db.QueryRow(r.rebind(query), since).
+		Scan(&stats.Users, &stats.Shows, &stats.Episodes, &stats.WatchedEpisodes, &stats.EpisodesWatchedSince)
+	return stats, err
+}
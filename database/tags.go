@@ -0,0 +1,70 @@
+package database
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// normalizeTags trim and lowercase tags, remove duplicates and sort them by name
+func normalizeTags(tags []string) (normalized []string, err error) {
+	normalized = []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || utf8.RuneCountInString(tag) > 50 {
+			return nil, ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// replaceTags remove all tags of movie and insert given ones
+func (r *SQLRepository) replaceTags(tx *sql.Tx, movieID int64, tags []string) error {
+	_, err := r.executeCount(tx, "DELETE FROM tag WHERE serial_id = ?;", movieID)
+	if err != nil {
+		return err
+	}
+	return r.insertTags(tx, movieID, tags)
+}
+
+func (r *SQLRepository) insertTags(tx *sql.Tx, movieID int64, tags []string) error {
+	for _, tag := range tags {
+		_, err := r.executeStmt(tx, "INSERT INTO tag (serial_id, name) VALUES (?, ?);", movieID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLRepository) retrieveTags(movieID int64) (tags []string, err error) {
+	rows, err := r.query("SELECT name FROM tag WHERE serial_id = ? ORDER BY name;", movieID)
+	if err != nil {
+		return tags, err
+	}
+	defer rows.Close()
+
+	tags = []string{}
+	for rows.Next() {
+		var tag string
+
+		err = rows.Scan(&tag)
+		if err != nil {
+			return tags, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
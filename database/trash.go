@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// TrashMovie function move movie of user to trash, it stays hidden until it is restored or purged
+func (r *SQLRepository) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return item, err
+	}
+
+	query := "SELECT id, name, COALESCE(url, '') FROM tv_series WHERE id = ? AND user_id = ? AND deleted_at IS NULL;"
+	err = tx.QueryRow(r.rebind(query), movieID, userID).Scan(&item.ID, &item.Name, &item.URL)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return item, ErrMovieNotFound
+	}
+	if err != nil {
+		tx.Rollback()
+		return models.TrashItem{}, err
+	}
+
+	item.DeletedAt = time.Now()
+	_, err = r.executeCount(tx, "UPDATE tv_series SET deleted_at = ? WHERE id = ?;", item.DeletedAt, movieID)
+	if err != nil {
+		tx.Rollback()
+		return models.TrashItem{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.TrashItem{}, err
+	}
+	return item, nil
+}
+
+// RestoreMovie function take movie of user back from trash, ErrMovieNotFound is returned when it is not in trash
+// and ErrRestoreNameTaken when other movie of user got its name in the meantime
+func (r *SQLRepository) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	result, err := r.db.Exec(r.rebind("UPDATE tv_series SET deleted_at = NULL WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL;"), movieID, userID)
+	if err != nil {
+		return movie, uniqueViolationAs(err, ErrRestoreNameTaken)
+	}
+
+	restored, err := result.RowsAffected()
+	if err != nil {
+		return movie, err
+	}
+	if restored == 0 {
+		return movie, ErrMovieNotFound
+	}
+	return r.RetrieveMovieDetail(userID, movieID)
+}
+
+// RetrieveTrash function return movies of user in trash, recently deleted first
+func (r *SQLRepository) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	rows, err := r.query("SELECT id, name, COALESCE(url, ''), deleted_at FROM tv_series WHERE user_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC;", userID)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+
+	items = models.TrashItems{}
+	for rows.Next() {
+		var item models.TrashItem
+
+		err = rows.Scan(&item.ID, &item.Name, &item.URL, &item.DeletedAt)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// PurgeTrash function permanently delete movies of all users moved to trash before deletedBefore,
+// all of them are removed in one transaction
+func (r *SQLRepository) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return deletion, err
+	}
+
+	movieIDs, err := r.trashedBefore(tx, deletedBefore)
+	if err != nil {
+		tx.Rollback()
+		return deletion, err
+	}
+
+	for _, movieID := range movieIDs {
+		removed, err := r.deleteMovieRows(tx, movieID)
+		if err != nil {
+			tx.Rollback()
+			return models.MovieDeletion{}, err
+		}
+		deletion.Movies += removed.Movies
+		deletion.Seasons += removed.Seasons
+		deletion.Episodes += removed.Episodes
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.MovieDeletion{}, err
+	}
+	return deletion, nil
+}
+
+func (r *SQLRepository) trashedBefore(tx *sql.Tx, deletedBefore time.Time) (movieIDs []int64, err error) {
+	rows, err := tx.Query(r.rebind("SELECT id FROM tv_series WHERE deleted_at IS NOT NULL AND deleted_at < ?;"), deletedBefore)
+	if err != nil {
+		return movieIDs, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieID int64
+
+		err = rows.Scan(&movieID)
+		if err != nil {
+			return movieIDs, err
+		}
+		movieIDs = append(movieIDs, movieID)
+	}
+	return movieIDs, rows.Err()
+}
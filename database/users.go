@@ -0,0 +1,96 @@
+package database
+
+import (
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+// CreateUser store new user in database. The first registered user is admin and becomes owner
+// of all movies created before user accounts existed, the following ones are editors.
+// Registrations are serialized by lock of users table, so only one user can become admin.
+func (r *SQLRepository) CreateUser(username string, passwordHash string) (user models.User, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return user, err
+	}
+
+	if r.dialect.lockUsers != "" {
+		_, err = tx.Exec(r.dialect.lockUsers)
+		if err != nil {
+			tx.Rollback()
+			return user, err
+		}
+	}
+
+	var usersCount, sameNameCount int
+	err = tx.QueryRow(r.rebind("SELECT COUNT(*), COALESCE(SUM(CASE WHEN username = ? THEN 1 ELSE 0 END), 0) FROM users;"), username).
+		Scan(&usersCount, &sameNameCount)
+	if err != nil {
+		tx.Rollback()
+		return user, err
+	}
+	if sameNameCount > 0 {
+		tx.Rollback()
+		return user, ErrDuplicateUserName
+	}
+
+	role := models.RoleEditor
+	if usersCount == 0 {
+		role = models.RoleAdmin
+	}
+
+	userID, err := r.executeStmt(tx, "INSERT INTO users (username, password_hash, role) VALUES (?, ?, ?);", username, passwordHash, role)
+	if err != nil {
+		tx.Rollback()
+		return user, uniqueViolationAs(err, ErrDuplicateUserName)
+	}
+
+	if usersCount == 0 {
+		_, err = r.executeStmt(tx, "UPDATE tv_series SET user_id = ? WHERE user_id IS NULL;", userID)
+		if err != nil {
+			tx.Rollback()
+			return user, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return user, err
+	}
+
+	return models.User{ID: userID, Username: username, Role: role, PasswordHash: passwordHash}, nil
+}
+
+// RetrieveUserByName found user with selected username
+func (r *SQLRepository) RetrieveUserByName(username string) (user models.User, err error) {
+	rows, err := r.query("SELECT id, username, role, password_hash FROM users WHERE username = ?;", username)
+	if err != nil {
+		return user, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if rows.Err() != nil {
+			return user, rows.Err()
+		}
+		return user, ErrUserNotFound
+	}
+
+	err = rows.Scan(&user.ID, &user.Username, &user.Role, &user.PasswordHash)
+	return user, err
+}
+
+// UpdateUserRole change role of user with selected username
+func (r *SQLRepository) UpdateUserRole(username string, role string) (user models.User, err error) {
+	user, err = r.RetrieveUserByName(username)
+	if err != nil {
+		return user, err
+	}
+
+	_, err = r.db.Exec(r.rebind("UPDATE users SET role = ? WHERE id = ?;"), role, user.ID)
+	if err != nil {
+		return user, err
+	}
+
+	user.Role = role
+	return user, nil
+}
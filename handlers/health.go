@@ -0,0 +1,55 @@
+package movies
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/Mowinski/LastWatchedBackend/utils"
+)
+
+// DefaultReadinessTimeout is used by ReadinessHandler when HealthHandlers has no Timeout
+const DefaultReadinessTimeout = 2 * time.Second
+
+// HealthHandlers join together liveness and readiness probes. CheckDatabase is called by readiness probe
+// with context canceled after Timeout, nil CheckDatabase means there is no database to check.
+type HealthHandlers struct {
+	CheckDatabase func(ctx context.Context) error
+	Timeout       time.Duration
+}
+
+// LivenessHandler respond OK as long as server handles requests
+func (hh HealthHandlers) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithJSON(w, http.StatusOK, models.HealthStatus{Status: models.HealthOK})
+}
+
+// ReadinessHandler respond OK when database is reachable, otherwise Service Unavailable is returned.
+// Probe is public, so reason of failed check is only logged.
+func (hh HealthHandlers) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	status := models.HealthStatus{Status: models.HealthOK, Checks: map[string]string{}}
+	if hh.CheckDatabase != nil {
+		timeout := hh.Timeout
+		if timeout <= 0 {
+			timeout = DefaultReadinessTimeout
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		status.Checks["database"] = models.HealthOK
+		err := hh.CheckDatabase(ctx)
+		if err != nil {
+			status.Status = models.HealthUnavailable
+			status.Checks["database"] = models.HealthUnavailable
+			logger.Logger.Error("Readiness check failed", "check", "database", "error", err, "request_id", logger.RequestIDFromContext(r.Context()))
+		}
+	}
+
+	code := http.StatusOK
+	if status.Status != models.HealthOK {
+		code = http.StatusServiceUnavailable
+	}
+	utils.RespondWithJSON(w, code, status)
+}
@@ -0,0 +1,99 @@
+package movies_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/handlers"
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	res := httptest.NewRecorder()
+
+	movies.HealthHandlers{}.LivenessHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	healthHandlers := movies.HealthHandlers{CheckDatabase: func(ctx context.Context) error { return nil }}
+
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	res := httptest.NewRecorder()
+
+	healthHandlers.ReadinessHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var status models.HealthStatus
+	json.Unmarshal(res.Body.Bytes(), &status)
+
+	if status.Status != models.HealthOK || status.Checks["database"] != models.HealthOK {
+		t.Errorf("Wrong status, got %v", status)
+	}
+}
+
+func TestReadinessHandlerDatabaseDown(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	healthHandlers := movies.HealthHandlers{CheckDatabase: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	res := httptest.NewRecorder()
+
+	healthHandlers.ReadinessHandler(res, req)
+
+	if res.Code != 503 {
+		t.Errorf("Wrong status code, expected 503, got %d", res.Code)
+	}
+
+	if strings.Contains(res.Body.String(), "connection refused") {
+		t.Errorf("Reason of failed check is revealed, got %s", res.Body.String())
+	}
+
+	var status models.HealthStatus
+	json.Unmarshal(res.Body.Bytes(), &status)
+
+	if status.Status != models.HealthUnavailable || status.Checks["database"] != models.HealthUnavailable {
+		t.Errorf("Wrong status, got %v", status)
+	}
+}
+
+func TestReadinessHandlerTimeout(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	healthHandlers := movies.HealthHandlers{
+		Timeout: 10 * time.Millisecond,
+		CheckDatabase: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	res := httptest.NewRecorder()
+
+	healthHandlers.ReadinessHandler(res, req)
+
+	if res.Code != 503 {
+		t.Errorf("Wrong status code, expected 503, got %d", res.Code)
+	}
+}
@@ -2,45 +2,47 @@ package movies_test
 
 import (
 	"fmt"
-	"io"
+	"net"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Mowinski/LastWatchedBackend/database"
 	"github.com/Mowinski/LastWatchedBackend/handlers"
 	"github.com/Mowinski/LastWatchedBackend/models"
-	"github.com/Mowinski/LastWatchedBackend/utils"
-
-	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
-type movieBodyPayload string
+type movieBodyPayload struct {
+	*strings.Reader
+}
 
 type movieTestHandlerData struct {
-	movieListRows          *sqlmock.Rows
-	movieDetailRow         *sqlmock.Rows
-	movieDetailLastWatched *sqlmock.Rows
-
 	movieCreatePayload                movieBodyPayload
 	movieUpdatePayload                movieBodyPayload
 	movieSuccessHandlers              movies.MovieHandlers
 	movieCreateFailedHandlers         movies.MovieHandlers
-	movieJSONParseFailedHandlers      movies.MovieHandlers
+	movieInvalidPayload               movieBodyPayload
+	movieOutOfRangePayload            movieBodyPayload
+	movieUnknownFieldPayload          movieBodyPayload
 	movieUpdateFailedHandlers         movies.MovieHandlers
 	movieDeleteFailedHandlers         movies.MovieHandlers
 	movieRetrieveDetailFailedHandlers movies.MovieHandlers
+	movieEpisodeFailedHandlers        movies.MovieHandlers
+	movieNotFoundHandlers             movies.MovieHandlers
 }
 
-func (m movieBodyPayload) Read(p []byte) (n int, err error) {
-	copy(p, m)
-	return len(m), nil
+func newMovieBodyPayload(body string) movieBodyPayload {
+	return movieBodyPayload{strings.NewReader(body)}
 }
+
 func (m movieBodyPayload) Close() error {
 	return nil
 }
 
-// MovieUtilsSuccessMocked
-type MovieUtilsSuccessMocked struct{}
+// MovieRepositorySuccessMocked
+type MovieRepositorySuccessMocked struct{}
 
-func (mh MovieUtilsSuccessMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositorySuccessMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
 	movie.ID = 1
 	movie.Name = "Test movie"
 	movie.URL = "http://www.example.com/test-movie"
@@ -51,7 +53,7 @@ func (mh MovieUtilsSuccessMocked) CreateMovie(payload models.MovieCreationPayloa
 	return movie, nil
 }
 
-func (mh MovieUtilsSuccessMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositorySuccessMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
 	movie.ID = id
 	movie.Name = payload.MovieName
 	movie.URL = payload.URL
@@ -62,15 +64,19 @@ func (mh MovieUtilsSuccessMocked) UpdateMovie(id int64, payload models.MovieUpda
 	return movie, nil
 }
 
-func (mh MovieUtilsSuccessMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return utils.GetJSONParameters(body, out)
+func (mr MovieRepositorySuccessMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return models.MovieDeletion{Movies: 1, Seasons: 2, Episodes: 18}, nil
 }
 
-func (mh MovieUtilsSuccessMocked) DeleteMovie(id int64) error {
-	return nil
+func (mr MovieRepositorySuccessMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	movies = models.MovieItems{
+		{ID: 1, Name: "Test Movie 1", URL: "http://www.example.com/movie1"},
+		{ID: 2, Name: "Test Movie 2", URL: "http://www.example.com/movie2"},
+	}
+	return movies, nil
 }
 
-func (mh MovieUtilsSuccessMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
+func (mr MovieRepositorySuccessMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	movie.ID = 1
 	movie.Name = "Test Movie 1"
 	movie.URL = "http://www.example.com/movie1"
@@ -81,119 +87,417 @@ func (mh MovieUtilsSuccessMocked) RetrieveMovieDetail(movieID int64) (movie mode
 	movie.SeriesCount = 5
 	return movie, nil
 }
-// MovieUtilsCreateFailedMocked
-type MovieUtilsCreateFailedMocked struct{}
 
-func (mh MovieUtilsCreateFailedMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
-	return movie, fmt.Errorf("Test error durring create movie")
+func (mr MovieRepositorySuccessMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	if seasonNumber > 5 || episodeNumber > 10 {
+		return movie, database.ErrEpisodeNotFound
+	}
+	movie, _ = mr.RetrieveMovieDetail(userID, movieID)
+	if watched {
+		movie.LastWatchedEpisode.Series = seasonNumber
+		movie.LastWatchedEpisode.EpisodeNumber = episodeNumber
+	}
+	return movie, nil
 }
 
-func (mh MovieUtilsCreateFailedMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositorySuccessMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	movie, _ = mr.RetrieveMovieDetail(userID, movieID)
+	movie.LastWatchedEpisode.ID = 3
+	movie.LastWatchedEpisode.EpisodeNumber = 4
 	return movie, nil
 }
 
-func (mh MovieUtilsCreateFailedMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return utils.GetJSONParameters(body, out)
+func (mr MovieRepositorySuccessMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	seasons = models.Seasons{
+		{Number: 1, EpisodesCount: 10, WatchedCount: 10, Progress: 100},
+		{Number: 2, EpisodesCount: 8, WatchedCount: 2, Progress: 25},
+	}
+	return seasons, nil
 }
 
-func (mh MovieUtilsCreateFailedMocked) DeleteMovie(id int64) error {
-	return nil
+func (mr MovieRepositorySuccessMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	if seasonNumber > 2 {
+		return episodes, database.ErrSeasonNotFound
+	}
+	date, _ := time.Parse(time.RFC822Z, "29 Jan 91 03:04 -0700")
+	episodes = models.EpisodeStates{
+		{Number: 1, Watched: true, Date: &date},
+		{Number: 2, Watched: false},
+	}
+	return episodes, nil
 }
 
-func (mh MovieUtilsCreateFailedMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
-	return movie, nil
+func (mr MovieRepositorySuccessMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	item = models.TrashItem{ID: movieID, Name: "Test Movie 1", URL: "http://www.example.com/movie1"}
+	item.DeletedAt, _ = time.Parse(time.RFC822Z, "29 Jan 91 03:04 -0700")
+	return item, nil
 }
 
-// MovieUtilsJSONParseFailedMocked
-type MovieUtilsJSONParseFailedMocked struct{}
+func (mr MovieRepositorySuccessMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return mr.RetrieveMovieDetail(userID, movieID)
+}
 
-func (mh MovieUtilsJSONParseFailedMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositorySuccessMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	item, _ := mr.TrashMovie(userID, 3)
+	return models.TrashItems{item}, nil
+}
+
+func (mr MovieRepositorySuccessMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return models.MovieDeletion{Movies: 2, Seasons: 3, Episodes: 20}, nil
+}
+
+// MovieRepositoryCreateFailedMocked
+type MovieRepositoryCreateFailedMocked struct{}
+
+func (mr MovieRepositoryCreateFailedMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
 	return movie, fmt.Errorf("Test error durring create movie")
 }
 
-func (mh MovieUtilsJSONParseFailedMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryCreateFailedMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieUtilsJSONParseFailedMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return fmt.Errorf("Test error during parsing JSON parameters")
+func (mr MovieRepositoryCreateFailedMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
 }
 
-func (mh MovieUtilsJSONParseFailedMocked) DeleteMovie(id int64) error {
-	return nil
+func (mr MovieRepositoryCreateFailedMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, nil
 }
 
-func (mh MovieUtilsJSONParseFailedMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryCreateFailedMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-// MovieUtilsUpdateMovieFailedMocked
-type MovieUtilsUpdateMovieFailedMocked struct{}
+func (mr MovieRepositoryCreateFailedMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
 
-func (mh MovieUtilsUpdateMovieFailedMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryCreateFailedMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieUtilsUpdateMovieFailedMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryCreateFailedMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, nil
+}
+
+func (mr MovieRepositoryCreateFailedMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, nil
+}
+
+func (mr MovieRepositoryCreateFailedMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, nil
+}
+
+func (mr MovieRepositoryCreateFailedMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryCreateFailedMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, nil
+}
+
+func (mr MovieRepositoryCreateFailedMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+// MovieRepositoryUpdateMovieFailedMocked
+type MovieRepositoryUpdateMovieFailedMocked struct{}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
 	return movie, fmt.Errorf("Test error during update movie")
 }
 
-func (mh MovieUtilsUpdateMovieFailedMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return nil
+func (mr MovieRepositoryUpdateMovieFailedMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
 }
 
-func (mh MovieUtilsUpdateMovieFailedMocked) DeleteMovie(id int64) error {
-	return nil
+func (mr MovieRepositoryUpdateMovieFailedMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, nil
 }
 
-func (mh MovieUtilsUpdateMovieFailedMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryUpdateMovieFailedMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-// MovieUtilsDeleteMovieFailedMocked
-type MovieUtilsDeleteMovieFailedMocked struct{}
+func (mr MovieRepositoryUpdateMovieFailedMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, nil
+}
 
-func (mh MovieUtilsDeleteMovieFailedMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryUpdateMovieFailedMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieUtilsDeleteMovieFailedMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryUpdateMovieFailedMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, nil
+}
+
+func (mr MovieRepositoryUpdateMovieFailedMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+// MovieRepositoryDeleteMovieFailedMocked
+type MovieRepositoryDeleteMovieFailedMocked struct{}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieUtilsDeleteMovieFailedMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return nil
+func (mr MovieRepositoryDeleteMovieFailedMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, fmt.Errorf("Test error during delete movie")
 }
 
-func (mh MovieUtilsDeleteMovieFailedMocked) DeleteMovie(id int64) error {
-	return fmt.Errorf("Test error during delete movie")
+func (mr MovieRepositoryDeleteMovieFailedMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, nil
 }
 
-func (mh MovieUtilsDeleteMovieFailedMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryDeleteMovieFailedMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	movie.ID = 1
 	return movie, nil
 }
 
+func (mr MovieRepositoryDeleteMovieFailedMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
 
-// MovieRetrieveDetailFailedMocked
-type MovieRetrieveDetailFailedMocked struct{}
+func (mr MovieRepositoryDeleteMovieFailedMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
 
-func (mh MovieRetrieveDetailFailedMocked) CreateMovie(payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryDeleteMovieFailedMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, nil
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, nil
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, fmt.Errorf("Test error during trash movie")
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, nil
+}
+
+func (mr MovieRepositoryDeleteMovieFailedMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, fmt.Errorf("Test error during purge trash")
+}
+
+// MovieRepositoryRetrieveDetailFailedMocked
+type MovieRepositoryRetrieveDetailFailedMocked struct{}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieRetrieveDetailFailedMocked) UpdateMovie(id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryRetrieveDetailFailedMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
 	return movie, nil
 }
 
-func (mh MovieRetrieveDetailFailedMocked) GetJSONParameters(body io.ReadCloser, out interface{}) error {
-	return nil
+func (mr MovieRepositoryRetrieveDetailFailedMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
 }
 
-func (mh MovieRetrieveDetailFailedMocked) DeleteMovie(id int64) error {
-	return nil
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, fmt.Errorf("Test error")
 }
 
-func (mh MovieRetrieveDetailFailedMocked) RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error) {
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
 	return movie, fmt.Errorf("Test error during retrieve")
 }
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, nil
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, nil
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, nil
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, fmt.Errorf("Test error during restore movie")
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, fmt.Errorf("Test error during retrieve trash")
+}
+
+func (mr MovieRepositoryRetrieveDetailFailedMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+// MovieRepositoryEpisodeFailedMocked
+type MovieRepositoryEpisodeFailedMocked struct{}
+
+func (mr MovieRepositoryEpisodeFailedMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	movie.ID = 1
+	return movie, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, fmt.Errorf("Test error during update episode")
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, fmt.Errorf("Test error during watch next episode")
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, fmt.Errorf("Test error during retrieve seasons")
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, fmt.Errorf("Test error during retrieve episodes")
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, nil
+}
+
+func (mr MovieRepositoryEpisodeFailedMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+// MovieRepositoryNotFoundMocked behave like repository without any movie of user
+type MovieRepositoryNotFoundMocked struct{}
+
+func (mr MovieRepositoryNotFoundMocked) CreateMovie(userID int64, payload models.MovieCreationPayload) (movie models.MovieDetail, err error) {
+	return movie, nil
+}
+
+func (mr MovieRepositoryNotFoundMocked) UpdateMovie(userID int64, id int64, payload models.MovieUpdatePayload) (movie models.MovieDetail, err error) {
+	return movie, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) DeleteMovie(userID int64, id int64) (deletion models.MovieDeletion, err error) {
+	return deletion, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	return movies, nil
+}
+
+func (mr MovieRepositoryNotFoundMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) SetEpisodeWatched(userID int64, movieID int64, seasonNumber int, episodeNumber int, watched bool) (movie models.MovieDetail, err error) {
+	return movie, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) WatchNextEpisode(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) RetrieveSeasons(userID int64, movieID int64) (seasons models.Seasons, err error) {
+	return seasons, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) RetrieveEpisodes(userID int64, movieID int64, seasonNumber int) (episodes models.EpisodeStates, err error) {
+	return episodes, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) TrashMovie(userID int64, movieID int64) (item models.TrashItem, err error) {
+	return item, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) RestoreMovie(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, database.ErrMovieNotFound
+}
+
+func (mr MovieRepositoryNotFoundMocked) RetrieveTrash(userID int64) (items models.TrashItems, err error) {
+	return items, nil
+}
+
+func (mr MovieRepositoryNotFoundMocked) PurgeTrash(deletedBefore time.Time) (deletion models.MovieDeletion, err error) {
+	return deletion, nil
+}
+
+// MovieRepositoryUserRecordingMocked remember user on behalf of which movies were listed and query of the list
+type MovieRepositoryUserRecordingMocked struct {
+	MovieRepositorySuccessMocked
+	userID *int64
+	query  *models.MovieListQuery
+}
+
+func (mr MovieRepositoryUserRecordingMocked) RetrieveMovieItems(userID int64, query models.MovieListQuery) (movies models.MovieItems, err error) {
+	*mr.userID = userID
+	if mr.query != nil {
+		*mr.query = query
+	}
+	return mr.MovieRepositorySuccessMocked.RetrieveMovieItems(userID, query)
+}
+
+// MovieRepositoryDatabaseDownMocked can not reach database when movie details are read
+type MovieRepositoryDatabaseDownMocked struct {
+	MovieRepositorySuccessMocked
+}
+
+func (mr MovieRepositoryDatabaseDownMocked) RetrieveMovieDetail(userID int64, movieID int64) (movie models.MovieDetail, err error) {
+	return movie, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+}
@@ -2,64 +2,53 @@ package movies_test
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/auth"
 	"github.com/Mowinski/LastWatchedBackend/logger"
 	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/Mowinski/LastWatchedBackend/validation"
 
 	"github.com/Mowinski/LastWatchedBackend/handlers"
 	"github.com/gorilla/mux"
-	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
-func setup(t *testing.T) (sqlmock.Sqlmock, movieTestHandlerData) {
+func setup(t *testing.T) movieTestHandlerData {
 	var testData movieTestHandlerData
-	date, _ := time.Parse(time.RFC822Z, "2017-01-02 18:42:20")
-
-	testData.movieListRows = sqlmock.NewRows([]string{"id", "name", "url"}).
-		AddRow(1, "Test Movie 1", "http://www.example.com/movie1").
-		AddRow(2, "Test Movie 2", "http://www.example.com/movie2")
-
-	testData.movieDetailRow = sqlmock.NewRows([]string{"id", "name", "url", "seriesCount"}).
-		AddRow(1, "Test Movie 1", "http://www.example.com/movie1", 5)
-	testData.movieDetailLastWatched = sqlmock.NewRows([]string{"id", "id", "number", "date"}).
-		AddRow(1, 1, 4, date)
-	testData.movieCreatePayload = "{\"movieName\":\"Marvel Runaways\",\"url\":\"www.google.com/url\",\"seriesNumber\":1,\"episodesInSeries\":10}"
-	testData.movieUpdatePayload = "{\"movieName\":\"Marvel Runaways New\",\"url\":\"www.google.com/new-url\",\"seriesNumber\": 1,\"episodesInSeries\": 10}"
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	var successUtils MovieUtilsSuccessMocked
-	var failedUtils MovieUtilsCreateFailedMocked
-	var jsonFailedUtils MovieUtilsJSONParseFailedMocked
-	var jsonUpdateFailedUtils MovieUtilsUpdateMovieFailedMocked
-	var movieDeleteFailedUtils MovieUtilsDeleteMovieFailedMocked
-	var movieRetrieveFailedUtils MovieRetrieveDetailFailedMocked
-
-	testData.movieSuccessHandlers = movies.MovieHandlers{Utils: successUtils}
-	testData.movieCreateFailedHandlers = movies.MovieHandlers{Utils: failedUtils}
-	testData.movieJSONParseFailedHandlers = movies.MovieHandlers{Utils: jsonFailedUtils}
-	testData.movieUpdateFailedHandlers = movies.MovieHandlers{Utils: jsonUpdateFailedUtils}
-	testData.movieDeleteFailedHandlers = movies.MovieHandlers{Utils: movieDeleteFailedUtils}
-	testData.movieRetrieveDetailFailedHandlers = movies.MovieHandlers{Utils: movieRetrieveFailedUtils}
-	database.SetDBConn(db)
-
-	return mock, testData
+
+	testData.movieCreatePayload = newMovieBodyPayload("{\"movieName\":\"Marvel Runaways\",\"url\":\"http://www.google.com/url\",\"seriesNumber\":1,\"episodesInSeries\":10}")
+	testData.movieUpdatePayload = newMovieBodyPayload("{\"movieName\":\"Marvel Runaways New\",\"url\":\"http://www.google.com/new-url\",\"seriesNumber\": 1,\"episodesInSeries\": 10}")
+	testData.movieInvalidPayload = newMovieBodyPayload("{\"movieName\":\"Marvel Runaways\",url: \"url with no quotation marks\"}")
+	testData.movieOutOfRangePayload = newMovieBodyPayload("{\"movieName\":\"\",\"url\":\"not a url\",\"seriesNumber\":-1,\"episodesInSeries\":1000000,\"seasons\":[{\"number\":0}]}")
+	testData.movieUnknownFieldPayload = newMovieBodyPayload("{\"movieName\":\"Marvel Runaways\",\"rating\":5}")
+
+	var successRepository MovieRepositorySuccessMocked
+	var createFailedRepository MovieRepositoryCreateFailedMocked
+	var updateFailedRepository MovieRepositoryUpdateMovieFailedMocked
+	var deleteFailedRepository MovieRepositoryDeleteMovieFailedMocked
+	var retrieveFailedRepository MovieRepositoryRetrieveDetailFailedMocked
+	var episodeFailedRepository MovieRepositoryEpisodeFailedMocked
+	var notFoundRepository MovieRepositoryNotFoundMocked
+
+	testData.movieSuccessHandlers = movies.MovieHandlers{Repository: successRepository}
+	testData.movieCreateFailedHandlers = movies.MovieHandlers{Repository: createFailedRepository}
+	testData.movieUpdateFailedHandlers = movies.MovieHandlers{Repository: updateFailedRepository}
+	testData.movieDeleteFailedHandlers = movies.MovieHandlers{Repository: deleteFailedRepository}
+	testData.movieRetrieveDetailFailedHandlers = movies.MovieHandlers{Repository: retrieveFailedRepository}
+	testData.movieEpisodeFailedHandlers = movies.MovieHandlers{Repository: episodeFailedRepository}
+	testData.movieNotFoundHandlers = movies.MovieHandlers{Repository: notFoundRepository}
+
+	return testData
 }
 
 func TestMovieListHandler(t *testing.T) {
-	mock, testData := setup(t)
-
-	mock.ExpectQuery("SELECT id, name, url FROM tv_series WHERE name LIKE (.+) LIMIT (.+) OFFSET (.+);").
-		WithArgs("%%", 50, 0).
-		WillReturnRows(testData.movieListRows)
+	testData := setup(t)
 
 	req, _ := http.NewRequest("GET", "/movies", nil)
 	res := httptest.NewRecorder()
@@ -104,45 +93,135 @@ func TestMovieListHandler(t *testing.T) {
 	}
 }
 
-func TestMovieListHandlerError(t *testing.T) {
-	mock, testData := setup(t)
+func TestMovieListHandlerAuthenticatedUser(t *testing.T) {
+	var userID int64
+	handlers := movies.MovieHandlers{Repository: MovieRepositoryUserRecordingMocked{userID: &userID}}
+
+	req, _ := http.NewRequest("GET", "/movies", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{User: models.User{ID: 7, Username: "john"}}))
+	res := httptest.NewRecorder()
+
+	handlers.MovieListHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	if userID != 7 {
+		t.Errorf("Wrong user passed to repository, expected 7, got %d", userID)
+	}
+}
+
+func TestMovieListHandlerQuery(t *testing.T) {
+	var userID int64
+	var query models.MovieListQuery
+	handlers := movies.MovieHandlers{Repository: MovieRepositoryUserRecordingMocked{userID: &userID, query: &query}}
+
+	req, _ := http.NewRequest("GET", "/movies?searchString=arr&status=in-progress&hasUnwatched=true&watchedWithinDays=7&tag=drama&sort=lastWatched&order=desc&limit=10&skip=5", nil)
+	res := httptest.NewRecorder()
+
+	handlers.MovieListHandler(res, req)
+
+	if res.Code != 200 {
+		t.Fatalf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	if query.SearchString != "%arr%" || query.Status != models.MovieStatusInProgress || query.Tag != "drama" ||
+		query.Sort != models.MovieSortLastWatched || query.Order != "desc" || query.Limit != 10 || query.Skip != 5 {
+		t.Errorf("Wrong query passed to repository, got %+v", query)
+	}
+
+	if query.HasUnwatched == nil || !*query.HasUnwatched {
+		t.Errorf("Wrong hasUnwatched filter, got %v", query.HasUnwatched)
+	}
+
+	weekAgo := time.Now().AddDate(0, 0, -7)
+	if query.WatchedSince == nil || query.WatchedSince.Sub(weekAgo) > time.Minute || weekAgo.Sub(*query.WatchedSince) > time.Minute {
+		t.Errorf("Wrong watched since filter, expected about %v, got %v", weekAgo, query.WatchedSince)
+	}
+}
+
+func TestMovieListHandlerDefaultQuery(t *testing.T) {
+	var userID int64
+	var query models.MovieListQuery
+	handlers := movies.MovieHandlers{Repository: MovieRepositoryUserRecordingMocked{userID: &userID, query: &query}}
+
+	req, _ := http.NewRequest("GET", "/movies", nil)
+	res := httptest.NewRecorder()
+
+	handlers.MovieListHandler(res, req)
+
+	expected := models.MovieListQuery{SearchString: "%%", Limit: 50}
+	if res.Code != 200 || query.HasUnwatched != nil || query.WatchedSince != nil || query.Status != "" || query.Sort != "" ||
+		query.SearchString != expected.SearchString || query.Limit != expected.Limit || query.Skip != 0 {
+		t.Errorf("Wrong default query, expected %+v, got %+v (status %d)", expected, query, res.Code)
+	}
+}
+
+func TestMovieListHandlerInvalidQuery(t *testing.T) {
+	testData := setup(t)
 	logger.SetLogger("test_log_file.txt")
 	defer os.Remove("test_log_file.txt")
 
-	mock.ExpectQuery("SELECT id, name, url FROM tv_series WHERE name LIKE (.+) LIMIT (.+) OFFSET (.+);").
-		WithArgs("%%", 50, 0).
-		WillReturnError(fmt.Errorf("Test error"))
+	cases := map[string]string{
+		"status=paused":          "Status",
+		"sort=id;DROP TABLE tag": "Sort",
+		"order=up":               "Order",
+		"limit=-1":               "Limit",
+		"hasUnwatched=maybe":     "hasUnwatched",
+		"watchedWithinDays=-1":   "watchedWithinDays",
+		"watchedWithinDays=week": "watchedWithinDays",
+	}
+
+	for rawQuery, field := range cases {
+		req, _ := http.NewRequest("GET", "/movies?"+url.PathEscape(rawQuery), nil)
+		res := httptest.NewRecorder()
+
+		testData.movieSuccessHandlers.MovieListHandler(res, req)
+
+		if res.Code != 422 {
+			t.Errorf("Wrong status code for %s, expected 422, got %d", rawQuery, res.Code)
+			continue
+		}
+
+		var response struct {
+			Details []validation.FieldError `json:"details"`
+		}
+		json.Unmarshal(res.Body.Bytes(), &response)
+
+		if len(response.Details) != 1 || response.Details[0].Field != field {
+			t.Errorf("Wrong invalid field for %s, expected %s, got %v", rawQuery, field, response.Details)
+		}
+	}
+}
+
+func TestMovieListHandlerError(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
 
 	req, _ := http.NewRequest("GET", "/movies", nil)
 	res := httptest.NewRecorder()
 
-	testData.movieSuccessHandlers.MovieListHandler(res, req)
+	testData.movieRetrieveDetailFailedHandlers.MovieListHandler(res, req)
 
-	if res.Code != 400 {
-		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
 	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error" {
-		t.Errorf("Wrong error message, expected 'Test error', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
 	}
 }
 
 func TestMovieDetailsHandler(t *testing.T) {
-	mock, testData := setup(t)
+	testData := setup(t)
 	logger.SetLogger("test_log_file.txt")
 	defer os.Remove("test_log_file.txt")
 
-	mock.ExpectQuery("SELECT tv_series.id, tv_series.name, url(.+)").
-		WithArgs(1).
-		WillReturnRows(testData.movieDetailRow)
-
-	mock.ExpectQuery("SELECT episode.id, season.id, episode.number, episode.date (.+)").
-		WithArgs().
-		WillReturnRows(testData.movieDetailLastWatched)
-
 	req, _ := http.NewRequest("GET", "/movie/1", nil)
 	res := httptest.NewRecorder()
 
@@ -175,7 +254,7 @@ func TestMovieDetailsHandler(t *testing.T) {
 }
 
 func TestMovieDetailsHandlerError(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 	logger.SetLogger("test_log_file.txt")
 	defer os.Remove("test_log_file.txt")
 
@@ -186,20 +265,64 @@ func TestMovieDetailsHandlerError(t *testing.T) {
 	m.HandleFunc("/movie/{id}", testData.movieRetrieveDetailFailedHandlers.MovieDetailsHandler).Methods("GET")
 	m.ServeHTTP(res, req)
 
-	if res.Code != 400 {
-		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
+	}
+}
+
+func TestMovieDetailsHandlerDatabaseDown(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("GET", "/movie/1", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}", movies.MovieHandlers{Repository: MovieRepositoryDatabaseDownMocked{}}.MovieDetailsHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 503 {
+		t.Errorf("Wrong status code, expected 503, got %d", res.Code)
 	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error during retrieve" {
-		t.Errorf("Wrong error message, expected 'Test error during retrieve', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "unavailable" || errorMsg["message"] != "Service is temporarily unavailable" {
+		t.Errorf("Connection error is not hidden, got: %v", errorMsg)
+	}
+}
+
+func TestMovieDetailsNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+	req, _ := http.NewRequest("GET", "/movie/999", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}", testData.movieNotFoundHandlers.MovieDetailsHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "not_found" || errorMsg["message"] != "Movie not found" {
+		t.Errorf("Wrong error, got: %v", errorMsg)
 	}
 }
 
 func TestMovieCreateHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 	req, _ := http.NewRequest("POST", "/movie", nil)
 	res := httptest.NewRecorder()
 	req.Body = testData.movieCreatePayload
@@ -242,31 +365,31 @@ func TestMovieCreateHandler(t *testing.T) {
 }
 
 func TestMovieCreateErrorHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 	req, _ := http.NewRequest("POST", "/movie", nil)
 	res := httptest.NewRecorder()
 	req.Body = testData.movieCreatePayload
 
 	testData.movieCreateFailedHandlers.MovieCreateHandler(res, req)
 
-	if res.Code != 400 {
-		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
 	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error durring create movie" {
-		t.Errorf("Wrong error message, expected 'Test error durring create movie', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
 	}
 }
 
 func TestMovieCreateParseJSONErrorHandler(t *testing.T) {
-	_, testData := setup(t)
-	req, _ := http.NewRequest("POST", "/movie", testData.movieCreatePayload)
+	testData := setup(t)
+	req, _ := http.NewRequest("POST", "/movie", testData.movieInvalidPayload)
 	res := httptest.NewRecorder()
 
-	testData.movieJSONParseFailedHandlers.MovieCreateHandler(res, req)
+	testData.movieSuccessHandlers.MovieCreateHandler(res, req)
 
 	if res.Code != 400 {
 		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
@@ -275,13 +398,67 @@ func TestMovieCreateParseJSONErrorHandler(t *testing.T) {
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error during parsing JSON parameters" {
-		t.Errorf("Wrong error message, expected 'Test error during parsing JSON parameters', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "bad_request" || errorMsg["details"] == "" {
+		t.Errorf("Wrong error, expected JSON parsing error, got: %v", errorMsg)
+	}
+}
+
+func TestMovieCreateValidationErrorHandler(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/movie", testData.movieOutOfRangePayload)
+	res := httptest.NewRecorder()
+
+	testData.movieSuccessHandlers.MovieCreateHandler(res, req)
+
+	if res.Code != 422 {
+		t.Errorf("Wrong status code, expected 422, got %d", res.Code)
+	}
+
+	var response struct {
+		Code    string
+		Details []validation.FieldError
+	}
+	json.Unmarshal(res.Body.Bytes(), &response)
+
+	fields := []string{}
+	for _, fieldError := range response.Details {
+		fields = append(fields, fieldError.Field)
+	}
+	expected := []string{"MovieName", "URL", "SeriesNumber", "EpisodesInSeries", "Seasons[0].Number"}
+	if response.Code != "validation_failed" || strings.Join(fields, ",") != strings.Join(expected, ",") {
+		t.Errorf("Wrong invalid fields, expected %v, got %v", expected, fields)
+	}
+}
+
+func TestMovieCreateUnknownFieldHandler(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/movie", testData.movieUnknownFieldPayload)
+	res := httptest.NewRecorder()
+
+	testData.movieSuccessHandlers.MovieCreateHandler(res, req)
+
+	if res.Code != 422 {
+		t.Errorf("Wrong status code, expected 422, got %d", res.Code)
+	}
+
+	var response struct {
+		Details []validation.FieldError
+	}
+	json.Unmarshal(res.Body.Bytes(), &response)
+
+	if len(response.Details) != 1 || response.Details[0].Field != "rating" {
+		t.Errorf("Unknown field is not reported, got %v", response.Details)
 	}
 }
 
 func TestMovieUpdateHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 	req, _ := http.NewRequest("PUT", "/movie/1", testData.movieUpdatePayload)
 	res := httptest.NewRecorder()
 
@@ -304,18 +481,18 @@ func TestMovieUpdateHandler(t *testing.T) {
 		t.Errorf("Wrong movie name, expected 'Marvel Runaways New', got %s", movieDetail.Name)
 	}
 
-	if movieDetail.URL != "www.google.com/new-url" {
-		t.Errorf("Wrong movie URL, expected 'www.google.com/new-url', got %s", movieDetail.URL)
+	if movieDetail.URL != "http://www.google.com/new-url" {
+		t.Errorf("Wrong movie URL, expected 'http://www.google.com/new-url', got %s", movieDetail.URL)
 	}
 }
 
 func TestMovieUpdateFailParametersHandler(t *testing.T) {
-	_, testData := setup(t)
-	req, _ := http.NewRequest("PUT", "/movie/1", testData.movieUpdatePayload)
+	testData := setup(t)
+	req, _ := http.NewRequest("PUT", "/movie/1", testData.movieInvalidPayload)
 	res := httptest.NewRecorder()
 
 	m := mux.NewRouter()
-	m.HandleFunc("/movie/{id}", testData.movieJSONParseFailedHandlers.MovieUpdateHandler).Methods("PUT")
+	m.HandleFunc("/movie/{id}", testData.movieSuccessHandlers.MovieUpdateHandler).Methods("PUT")
 	m.ServeHTTP(res, req)
 
 	if res.Code != 400 {
@@ -325,13 +502,13 @@ func TestMovieUpdateFailParametersHandler(t *testing.T) {
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error during parsing JSON parameters" {
-		t.Errorf("Wrong error message, expected 'Test error during parsing JSON parameters', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "bad_request" || errorMsg["details"] == "" {
+		t.Errorf("Wrong error, expected JSON parsing error, got: %v", errorMsg)
 	}
 }
 
 func TestMovieUpdateFailUpdateHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 	req, _ := http.NewRequest("PUT", "/movie/1", testData.movieUpdatePayload)
 	res := httptest.NewRecorder()
 
@@ -339,25 +516,25 @@ func TestMovieUpdateFailUpdateHandler(t *testing.T) {
 	m.HandleFunc("/movie/{id}", testData.movieUpdateFailedHandlers.MovieUpdateHandler).Methods("PUT")
 	m.ServeHTTP(res, req)
 
-	if res.Code != 400 {
-		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
 	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error during update movie" {
-		t.Errorf("Wrong error message, expected 'Test error during update movie', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
 	}
 }
 
-func TestMovieUpdateFailRetrieveMovieHandler(t *testing.T) {
-	_, testData := setup(t)
+func TestMovieUpdateNotFoundHandler(t *testing.T) {
+	testData := setup(t)
 	req, _ := http.NewRequest("PUT", "/movie/1", testData.movieUpdatePayload)
 	res := httptest.NewRecorder()
 
 	m := mux.NewRouter()
-	m.HandleFunc("/movie/{id}", testData.movieRetrieveDetailFailedHandlers.MovieUpdateHandler).Methods("PUT")
+	m.HandleFunc("/movie/{id}", testData.movieNotFoundHandlers.MovieUpdateHandler).Methods("PUT")
 	m.ServeHTTP(res, req)
 
 	if res.Code != 404 {
@@ -367,13 +544,13 @@ func TestMovieUpdateFailRetrieveMovieHandler(t *testing.T) {
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "" {
-		t.Errorf("Wrong error message, expected '', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "not_found" {
+		t.Errorf("Wrong error code, expected 'not_found', got: %s", errorMsg["code"])
 	}
 }
 
 func TestMovieDeleteHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 
 	req, _ := http.NewRequest("DELETE", "/movie/1", nil)
 	res := httptest.NewRecorder()
@@ -385,10 +562,39 @@ func TestMovieDeleteHandler(t *testing.T) {
 	if res.Code != 200 {
 		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
 	}
+
+	var item models.TrashItem
+	json.Unmarshal(res.Body.Bytes(), &item)
+
+	if item.ID != 1 || item.DeletedAt.IsZero() {
+		t.Errorf("Movie is not moved to trash, got %v", item)
+	}
+}
+
+func TestMovieDeletePermanentHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("DELETE", "/movie/1?permanent=true", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}", testData.movieSuccessHandlers.MovieDeleteHandler).Methods("DELETE")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var deletion models.MovieDeletion
+	json.Unmarshal(res.Body.Bytes(), &deletion)
+
+	if deletion.Movies != 1 || deletion.Seasons != 2 || deletion.Episodes != 18 {
+		t.Errorf("Wrong number of removed rows, expected 1 movie, 2 seasons and 18 episodes, got %v", deletion)
+	}
 }
 
 func TestMovieDeleteFailedHandler(t *testing.T) {
-	_, testData := setup(t)
+	testData := setup(t)
 
 	req, _ := http.NewRequest("DELETE", "/movie/1", nil)
 	res := httptest.NewRecorder()
@@ -397,36 +603,363 @@ func TestMovieDeleteFailedHandler(t *testing.T) {
 	m.HandleFunc("/movie/{id}", testData.movieDeleteFailedHandlers.MovieDeleteHandler).Methods("DELETE")
 	m.ServeHTTP(res, req)
 
-	if res.Code != 400 {
-		t.Errorf("Wrong status code, expected 400, got %d", res.Code)
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
 	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "Test error during delete movie" {
-		t.Errorf("Wrong error message, expected 'Test error during delete movie', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
 	}
 }
 
-func TestMovieDeleteRetrieveFailedHandler(t *testing.T) {
-	_, testData := setup(t)
+func TestMovieDeletePermanentFailedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("DELETE", "/movie/1?permanent=true", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}", testData.movieDeleteFailedHandlers.MovieDeleteHandler).Methods("DELETE")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
+
+func TestMovieDeleteNotFoundHandler(t *testing.T) {
+	testData := setup(t)
 
 	req, _ := http.NewRequest("DELETE", "/movie/1", nil)
 	res := httptest.NewRecorder()
 
 	m := mux.NewRouter()
-	m.HandleFunc("/movie/{id}", testData.movieRetrieveDetailFailedHandlers.MovieDeleteHandler).Methods("DELETE")
+	m.HandleFunc("/movie/{id}", testData.movieNotFoundHandlers.MovieDeleteHandler).Methods("DELETE")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "not_found" {
+		t.Errorf("Wrong error code, expected 'not_found', got: %s", errorMsg["code"])
+	}
+}
+
+func TestEpisodeWatchedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("PUT", "/movie/1/season/2/episode/7/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieSuccessHandlers.EpisodeWatchedHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var movieDetail models.MovieDetail
+	json.Unmarshal(res.Body.Bytes(), &movieDetail)
+
+	if movieDetail.LastWatchedEpisode.Series != 2 {
+		t.Errorf("Wrong last watched episode series, expected 2, got %d", movieDetail.LastWatchedEpisode.Series)
+	}
+
+	if movieDetail.LastWatchedEpisode.EpisodeNumber != 7 {
+		t.Errorf("Wrong last watched episode, expected 7, got %d", movieDetail.LastWatchedEpisode.EpisodeNumber)
+	}
+}
+
+func TestEpisodeUnwatchedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("DELETE", "/movie/1/season/2/episode/7/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieSuccessHandlers.EpisodeUnwatchedHandler).Methods("DELETE")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+}
+
+func TestEpisodeWatchedEpisodeNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("PUT", "/movie/1/season/2/episode/11/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieSuccessHandlers.EpisodeWatchedHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestEpisodeWatchedMovieNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("PUT", "/movie/1/season/2/episode/7/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieNotFoundHandlers.EpisodeWatchedHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestEpisodeUnwatchedMovieNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("DELETE", "/movie/1/season/2/episode/7/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieNotFoundHandlers.EpisodeUnwatchedHandler).Methods("DELETE")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestEpisodeWatchedFailedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("PUT", "/movie/1/season/2/episode/7/watched", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episode/{episode}/watched", testData.movieEpisodeFailedHandlers.EpisodeWatchedHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
+	}
+}
+
+func TestMovieWatchNextHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("POST", "/movie/1/next", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/next", testData.movieSuccessHandlers.MovieWatchNextHandler).Methods("POST")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var movieDetail models.MovieDetail
+	json.Unmarshal(res.Body.Bytes(), &movieDetail)
+
+	if movieDetail.LastWatchedEpisode.EpisodeNumber != 4 {
+		t.Errorf("Wrong last watched episode, expected 4, got %d", movieDetail.LastWatchedEpisode.EpisodeNumber)
+	}
+}
+
+func TestMovieWatchNextNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("POST", "/movie/1/next", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/next", testData.movieNotFoundHandlers.MovieWatchNextHandler).Methods("POST")
 	m.ServeHTTP(res, req)
 
 	if res.Code != 404 {
 		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
 	}
+}
+
+func TestMovieWatchNextFailedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("POST", "/movie/1/next", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/next", testData.movieEpisodeFailedHandlers.MovieWatchNextHandler).Methods("POST")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
 
 	var errorMsg map[string]string
 	json.Unmarshal(res.Body.Bytes(), &errorMsg)
 
-	if errorMsg["error"] != "" {
-		t.Errorf("Wrong error message, expected '', got: %s", errorMsg["error"])
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
+	}
+}
+
+func TestMovieSeasonsHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/seasons", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/seasons", testData.movieSuccessHandlers.MovieSeasonsHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var seasons models.Seasons
+	json.Unmarshal(res.Body.Bytes(), &seasons)
+
+	if len(seasons) != 2 {
+		t.Fatalf("Wrong number of seasons, expected 2, got %d", len(seasons))
+	}
+
+	if seasons[1].Progress != 25 {
+		t.Errorf("Wrong season progress, expected 25, got %f", seasons[1].Progress)
+	}
+}
+
+func TestMovieSeasonsNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/seasons", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/seasons", testData.movieNotFoundHandlers.MovieSeasonsHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestMovieSeasonsFailedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/seasons", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/seasons", testData.movieEpisodeFailedHandlers.MovieSeasonsHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "internal" || errorMsg["message"] != "Internal server error" {
+		t.Errorf("Internal error is not hidden, got: %v", errorMsg)
+	}
+}
+
+func TestSeasonEpisodesHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/season/1/episodes", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episodes", testData.movieSuccessHandlers.SeasonEpisodesHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var episodes models.EpisodeStates
+	json.Unmarshal(res.Body.Bytes(), &episodes)
+
+	if len(episodes) != 2 {
+		t.Fatalf("Wrong number of episodes, expected 2, got %d", len(episodes))
+	}
+
+	if !episodes[0].Watched || episodes[0].Date == nil {
+		t.Errorf("Wrong state of first episode, expected watched with date, got %v", episodes[0])
+	}
+
+	if episodes[1].Watched || episodes[1].Date != nil {
+		t.Errorf("Wrong state of second episode, expected unwatched without date, got %v", episodes[1])
+	}
+}
+
+func TestSeasonEpisodesSeasonNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/season/3/episodes", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episodes", testData.movieSuccessHandlers.SeasonEpisodesHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestSeasonEpisodesMovieNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/season/1/episodes", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episodes", testData.movieNotFoundHandlers.SeasonEpisodesHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["message"] != "Movie not found" {
+		t.Errorf("Wrong error message, expected 'Movie not found', got: %s", errorMsg["message"])
+	}
+}
+
+func TestSeasonEpisodesFailedHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/movie/1/season/1/episodes", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/season/{season}/episodes", testData.movieEpisodeFailedHandlers.SeasonEpisodesHandler).Methods("GET")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
 	}
 }
@@ -2,53 +2,91 @@
 package movies
 
 import (
-	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/database"
 	"github.com/Mowinski/LastWatchedBackend/models"
 	"github.com/Mowinski/LastWatchedBackend/utils"
+	"github.com/Mowinski/LastWatchedBackend/validation"
 )
 
-// MovieUtils interface describe all utility function in handlers
-type MovieUtils interface {
-	CreateMovie(payload models.MovieCreationPayload) (models.MovieDetail, error)
-	UpdateMovie(id int64, payload models.MovieUpdatePayload) (models.MovieDetail, error)
-	DeleteMovie(id int64) error
-
-	GetJSONParameters(body io.ReadCloser, out interface{}) error
-	RetrieveMovieDetail(movieID int64) (movie models.MovieDetail, err error)
+// MovieHandlers join together all movie handlers, all data is read and written through Repository
+// on behalf of user authenticated for request. Movie which does not exist or belongs to other user
+// is reported by Repository as database.ErrMovieNotFound and answered with 404.
+type MovieHandlers struct {
+	Repository database.MovieRepository
 }
 
-// MovieHandlers join together all movie handlers
-type MovieHandlers struct {
-	Utils MovieUtils
+// currentUserID return ID of user authenticated for request
+func currentUserID(r *http.Request) int64 {
+	user, _ := auth.UserFromContext(r.Context())
+	return user.ID
 }
 
-// MovieListHandler is responsive for return movie list
+// MovieListHandler is responsive for return movie list, it can be filtered by searchString, status,
+// hasUnwatched, watchedWithinDays and tag and sorted by sort column in order direction
 func (mh MovieHandlers) MovieListHandler(w http.ResponseWriter, r *http.Request) {
-	searchString := "%" + r.URL.Query().Get("searchString") + "%"
-
-	skip := utils.GetIntOrDefault(r.URL.Query().Get("skip"), 0)
-	limit := utils.GetIntOrDefault(r.URL.Query().Get("limit"), 50)
+	query, err := movieListQuery(r.URL.Query())
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
 
-	movies, err := retrieveMovieItems(searchString, limit, skip)
+	movies, err := mh.Repository.RetrieveMovieItems(currentUserID(r), query)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, movies)
 }
 
+// movieListQuery read movie list query from URL parameters
+func movieListQuery(values url.Values) (query models.MovieListQuery, err error) {
+	query = models.MovieListQuery{
+		SearchString: "%" + values.Get("searchString") + "%",
+		Status:       values.Get("status"),
+		Tag:          values.Get("tag"),
+		Sort:         values.Get("sort"),
+		Order:        values.Get("order"),
+		Skip:         utils.GetIntOrDefault(values.Get("skip"), 0),
+		Limit:        utils.GetIntOrDefault(values.Get("limit"), 50),
+	}
+
+	var fieldErrors []validation.FieldError
+	if value := values.Get("hasUnwatched"); value != "" {
+		hasUnwatched, parseErr := strconv.ParseBool(value)
+		if parseErr != nil {
+			fieldErrors = append(fieldErrors, validation.FieldError{Field: "hasUnwatched", Message: "has to be true or false"})
+		}
+		query.HasUnwatched = &hasUnwatched
+	}
+	if value := values.Get("watchedWithinDays"); value != "" {
+		days, parseErr := strconv.Atoi(value)
+		if parseErr != nil || days < 0 {
+			fieldErrors = append(fieldErrors, validation.FieldError{Field: "watchedWithinDays", Message: "has to be at least 0"})
+		}
+		since := time.Now().AddDate(0, 0, -days)
+		query.WatchedSince = &since
+	}
+	if len(fieldErrors) > 0 {
+		return query, validation.Fields(fieldErrors...)
+	}
+	return query, validation.Struct(query)
+}
+
 // MovieDetailsHandler is responsive for return movie detials
 func (mh MovieHandlers) MovieDetailsHandler(w http.ResponseWriter, r *http.Request) {
 	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 
-	movie, err := mh.Utils.RetrieveMovieDetail(movieID)
+	movie, err := mh.Repository.RetrieveMovieDetail(currentUserID(r), movieID)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 	utils.RespondWithJSON(w, http.StatusOK, movie)
@@ -57,15 +95,15 @@ func (mh MovieHandlers) MovieDetailsHandler(w http.ResponseWriter, r *http.Reque
 // MovieCreateHandler create new movie in database
 func (mh MovieHandlers) MovieCreateHandler(w http.ResponseWriter, r *http.Request) {
 	var payload models.MovieCreationPayload
-	err := mh.Utils.GetJSONParameters(r.Body, &payload)
+	err := utils.GetJSONParameters(r.Body, &payload)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
-	movie, err := mh.Utils.CreateMovie(payload)
+	movie, err := mh.Repository.CreateMovie(currentUserID(r), payload)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
@@ -76,45 +114,108 @@ func (mh MovieHandlers) MovieCreateHandler(w http.ResponseWriter, r *http.Reques
 func (mh MovieHandlers) MovieUpdateHandler(w http.ResponseWriter, r *http.Request) {
 	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	var payload models.MovieUpdatePayload
-	err := mh.Utils.GetJSONParameters(r.Body, &payload)
+	err := utils.GetJSONParameters(r.Body, &payload)
+
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
 
+	movie, err := mh.Repository.UpdateMovie(currentUserID(r), movieID, payload)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
-	_, err = mh.Utils.RetrieveMovieDetail(movieID)
+	utils.RespondWithJSON(w, http.StatusOK, movie)
+}
+
+// MovieDeleteHandler move movie to trash and return it, with permanent=true query parameter movie is removed
+// with its seasons and episodes from database and number of removed rows is returned
+func (mh MovieHandlers) MovieDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+
+	if r.URL.Query().Get("permanent") != "true" {
+		item, err := mh.Repository.TrashMovie(currentUserID(r), movieID)
+		if err != nil {
+			utils.RespondWithError(w, r, err)
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, item)
+		return
+	}
 
+	deletion, err := mh.Repository.DeleteMovie(currentUserID(r), movieID)
 	if err != nil {
-		utils.RespondWithJSON(w, http.StatusNotFound, nil)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
-	movie, err := mh.Utils.UpdateMovie(movieID, payload)
+	utils.RespondWithJSON(w, http.StatusOK, deletion)
+}
+
+// EpisodeWatchedHandler mark selected episode as watched
+func (mh MovieHandlers) EpisodeWatchedHandler(w http.ResponseWriter, r *http.Request) {
+	mh.setEpisodeWatched(w, r, true)
+}
+
+// EpisodeUnwatchedHandler mark selected episode as unwatched
+func (mh MovieHandlers) EpisodeUnwatchedHandler(w http.ResponseWriter, r *http.Request) {
+	mh.setEpisodeWatched(w, r, false)
+}
+
+func (mh MovieHandlers) setEpisodeWatched(w http.ResponseWriter, r *http.Request, watched bool) {
+	vars := mux.Vars(r)
+	movieID, _ := strconv.ParseInt(vars["id"], 10, 64)
+	seasonNumber := utils.GetIntOrDefault(vars["season"], 0)
+	episodeNumber := utils.GetIntOrDefault(vars["episode"], 0)
+
+	movie, err := mh.Repository.SetEpisodeWatched(currentUserID(r), movieID, seasonNumber, episodeNumber, watched)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
 	utils.RespondWithJSON(w, http.StatusOK, movie)
 }
 
-// MovieDeleteHandler remove movie from database
-func (mh MovieHandlers) MovieDeleteHandler(w http.ResponseWriter, r *http.Request) {
+// MovieWatchNextHandler mark as watched next episode after the last watched one
+func (mh MovieHandlers) MovieWatchNextHandler(w http.ResponseWriter, r *http.Request) {
 	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 
-	movie, err := mh.Utils.RetrieveMovieDetail(movieID)
+	movie, err := mh.Repository.WatchNextEpisode(currentUserID(r), movieID)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, movie)
+}
 
-	if err != nil || movie.ID == 0 {
-		utils.RespondWithJSON(w, http.StatusNotFound, nil)
+// MovieSeasonsHandler return all seasons of movie with watch progress
+func (mh MovieHandlers) MovieSeasonsHandler(w http.ResponseWriter, r *http.Request) {
+	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+
+	seasons, err := mh.Repository.RetrieveSeasons(currentUserID(r), movieID)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
-	err = mh.Utils.DeleteMovie(movieID)
+	utils.RespondWithJSON(w, http.StatusOK, seasons)
+}
+
+// SeasonEpisodesHandler return watch state of every episode in selected season
+func (mh MovieHandlers) SeasonEpisodesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	movieID, _ := strconv.ParseInt(vars["id"], 10, 64)
+	seasonNumber := utils.GetIntOrDefault(vars["season"], 0)
+
+	episodes, err := mh.Repository.RetrieveEpisodes(currentUserID(r), movieID, seasonNumber)
 	if err != nil {
-		utils.ResponseBadRequestError(w, err)
+		utils.RespondWithError(w, r, err)
 		return
 	}
 
-	utils.RespondWithJSON(w, http.StatusOK, nil)
+	utils.RespondWithJSON(w, http.StatusOK, episodes)
 }
@@ -0,0 +1,54 @@
+package movies
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Mowinski/LastWatchedBackend/utils"
+	"github.com/Mowinski/LastWatchedBackend/validation"
+)
+
+// DefaultPurgeDays is age in days of movies removed by TrashPurgeHandler when olderThanDays is not given
+const DefaultPurgeDays = 30
+
+// TrashHandler return movies of user moved to trash
+func (mh MovieHandlers) TrashHandler(w http.ResponseWriter, r *http.Request) {
+	items, err := mh.Repository.RetrieveTrash(currentUserID(r))
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, items)
+}
+
+// MovieRestoreHandler take movie back from trash and return its details
+func (mh MovieHandlers) MovieRestoreHandler(w http.ResponseWriter, r *http.Request) {
+	movieID, _ := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+
+	movie, err := mh.Repository.RestoreMovie(currentUserID(r), movieID)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, movie)
+}
+
+// TrashPurgeHandler permanently delete movies of all users which are in trash longer than olderThanDays
+// query parameter (DefaultPurgeDays by default) and return number of removed rows
+func (mh MovieHandlers) TrashPurgeHandler(w http.ResponseWriter, r *http.Request) {
+	days := utils.GetIntOrDefault(r.URL.Query().Get("olderThanDays"), DefaultPurgeDays)
+	if days < 0 {
+		utils.RespondWithError(w, r, validation.Fields(validation.FieldError{Field: "olderThanDays", Message: "has to be at least 0"}))
+		return
+	}
+
+	deletion, err := mh.Repository.PurgeTrash(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, deletion)
+}
@@ -0,0 +1,159 @@
+package movies_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/gorilla/mux"
+)
+
+func TestTrashHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("GET", "/trash", nil)
+	res := httptest.NewRecorder()
+
+	testData.movieSuccessHandlers.TrashHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var items models.TrashItems
+	json.Unmarshal(res.Body.Bytes(), &items)
+
+	if len(items) != 1 || items[0].ID != 3 || items[0].DeletedAt.IsZero() {
+		t.Errorf("Wrong trash, expected movie 3 with deletion date, got %v", items)
+	}
+}
+
+func TestTrashHandlerError(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("GET", "/trash", nil)
+	res := httptest.NewRecorder()
+
+	testData.movieRetrieveDetailFailedHandlers.TrashHandler(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
+
+func TestMovieRestoreHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("POST", "/movie/1/restore", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/restore", testData.movieSuccessHandlers.MovieRestoreHandler).Methods("POST")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var movie models.MovieDetail
+	json.Unmarshal(res.Body.Bytes(), &movie)
+
+	if movie.ID != 1 {
+		t.Errorf("Wrong movie, expected ID 1, got %v", movie)
+	}
+}
+
+func TestMovieRestoreNotFoundHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("POST", "/movie/1/restore", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/restore", testData.movieNotFoundHandlers.MovieRestoreHandler).Methods("POST")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "not_found" {
+		t.Errorf("Wrong error code, expected 'not_found', got: %s", errorMsg["code"])
+	}
+}
+
+func TestMovieRestoreFailedHandler(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/movie/1/restore", nil)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/movie/{id}/restore", testData.movieRetrieveDetailFailedHandlers.MovieRestoreHandler).Methods("POST")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
+
+func TestTrashPurgeHandler(t *testing.T) {
+	testData := setup(t)
+
+	req, _ := http.NewRequest("DELETE", "/trash?olderThanDays=7", nil)
+	res := httptest.NewRecorder()
+
+	testData.movieSuccessHandlers.TrashPurgeHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var deletion models.MovieDeletion
+	json.Unmarshal(res.Body.Bytes(), &deletion)
+
+	if deletion.Movies != 2 || deletion.Seasons != 3 || deletion.Episodes != 20 {
+		t.Errorf("Wrong number of removed rows, expected 2 movies, 3 seasons and 20 episodes, got %v", deletion)
+	}
+}
+
+func TestTrashPurgeNegativeDaysHandler(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("DELETE", "/trash?olderThanDays=-1", nil)
+	res := httptest.NewRecorder()
+
+	testData.movieSuccessHandlers.TrashPurgeHandler(res, req)
+
+	if res.Code != 422 {
+		t.Errorf("Wrong status code, expected 422, got %d", res.Code)
+	}
+}
+
+func TestTrashPurgeFailedHandler(t *testing.T) {
+	testData := setup(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("DELETE", "/trash", nil)
+	res := httptest.NewRecorder()
+
+	testData.movieDeleteFailedHandlers.TrashPurgeHandler(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
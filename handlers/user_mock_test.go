@@ -0,0 +1,60 @@
+package movies_test
+
+import (
+	"fmt"
+
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/handlers"
+	"github.com/Mowinski/LastWatchedBackend/models"
+)
+
+type userTestHandlerData struct {
+	userPayload              movieBodyPayload
+	userWrongPasswordPayload movieBodyPayload
+	userEmptyPayload         movieBodyPayload
+	rolePayload              movieBodyPayload
+	invalidRolePayload       movieBodyPayload
+	userSuccessHandlers      movies.UserHandlers
+	userFailedHandlers       movies.UserHandlers
+}
+
+// UserRepositorySuccessMocked
+type UserRepositorySuccessMocked struct {
+	passwordHash string
+}
+
+func (mr UserRepositorySuccessMocked) CreateUser(username string, passwordHash string) (user models.User, err error) {
+	return models.User{ID: 1, Username: username, PasswordHash: passwordHash}, nil
+}
+
+func (mr UserRepositorySuccessMocked) RetrieveUserByName(username string) (user models.User, err error) {
+	return models.User{ID: 1, Username: username, PasswordHash: mr.passwordHash}, nil
+}
+
+func (mr UserRepositorySuccessMocked) UpdateUserRole(username string, role string) (user models.User, err error) {
+	if username != "john" {
+		return user, database.ErrUserNotFound
+	}
+	return models.User{ID: 1, Username: username, Role: role, PasswordHash: mr.passwordHash}, nil
+}
+
+// UserRepositoryFailedMocked
+type UserRepositoryFailedMocked struct{}
+
+func (mr UserRepositoryFailedMocked) CreateUser(username string, passwordHash string) (user models.User, err error) {
+	return user, database.ErrDuplicateUserName
+}
+
+func (mr UserRepositoryFailedMocked) RetrieveUserByName(username string) (user models.User, err error) {
+	return user, fmt.Errorf("Test error during retrieve user")
+}
+
+func (mr UserRepositoryFailedMocked) UpdateUserRole(username string, role string) (user models.User, err error) {
+	return user, fmt.Errorf("Test error during update user role")
+}
+
+func newUserRepositorySuccessMocked(password string) UserRepositorySuccessMocked {
+	passwordHash, _ := auth.HashPassword(password)
+	return UserRepositorySuccessMocked{passwordHash: passwordHash}
+}
@@ -0,0 +1,217 @@
+package movies_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/handlers"
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/gorilla/mux"
+)
+
+func setupUsers(t *testing.T) userTestHandlerData {
+	var testData userTestHandlerData
+
+	testData.userPayload = newMovieBodyPayload("{\"username\":\"john\",\"password\":\"secret\"}")
+	testData.userWrongPasswordPayload = newMovieBodyPayload("{\"username\":\"john\",\"password\":\"wrong\"}")
+	testData.userEmptyPayload = newMovieBodyPayload("{\"username\":\"john\"}")
+	testData.rolePayload = newMovieBodyPayload("{\"role\":\"viewer\"}")
+	testData.invalidRolePayload = newMovieBodyPayload("{\"role\":\"owner\"}")
+
+	tokens := auth.NewJWT("token secret", time.Hour)
+	testData.userSuccessHandlers = movies.UserHandlers{Repository: newUserRepositorySuccessMocked("secret"), Tokens: tokens}
+	testData.userFailedHandlers = movies.UserHandlers{Repository: UserRepositoryFailedMocked{}, Tokens: tokens}
+
+	return testData
+}
+
+func TestRegisterHandler(t *testing.T) {
+	testData := setupUsers(t)
+
+	req, _ := http.NewRequest("POST", "/register", testData.userPayload)
+	res := httptest.NewRecorder()
+
+	testData.userSuccessHandlers.RegisterHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &response)
+
+	if response["Username"] != "john" {
+		t.Errorf("Wrong username, expected 'john', got %v", response["Username"])
+	}
+
+	if _, ok := response["PasswordHash"]; ok {
+		t.Error("Password hash is sent to client")
+	}
+}
+
+func TestRegisterHandlerMissingPassword(t *testing.T) {
+	testData := setupUsers(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/register", testData.userEmptyPayload)
+	res := httptest.NewRecorder()
+
+	testData.userSuccessHandlers.RegisterHandler(res, req)
+
+	if res.Code != 422 {
+		t.Errorf("Wrong status code, expected 422, got %d", res.Code)
+	}
+}
+
+func TestRegisterHandlerDuplicatedUser(t *testing.T) {
+	testData := setupUsers(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/register", testData.userPayload)
+	res := httptest.NewRecorder()
+
+	testData.userFailedHandlers.RegisterHandler(res, req)
+
+	if res.Code != 409 {
+		t.Errorf("Wrong status code, expected 409, got %d", res.Code)
+	}
+
+	var errorMsg map[string]string
+	json.Unmarshal(res.Body.Bytes(), &errorMsg)
+
+	if errorMsg["code"] != "conflict" || errorMsg["message"] != "User with this username already exists" {
+		t.Errorf("Wrong error, got: %v", errorMsg)
+	}
+}
+
+func TestLoginHandler(t *testing.T) {
+	testData := setupUsers(t)
+
+	req, _ := http.NewRequest("POST", "/login", testData.userPayload)
+	res := httptest.NewRecorder()
+
+	testData.userSuccessHandlers.LoginHandler(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var token models.AuthToken
+	json.Unmarshal(res.Body.Bytes(), &token)
+
+	if token.User.ID != 1 || token.User.Username != "john" {
+		t.Errorf("Wrong user, expected 1 'john', got %v", token.User)
+	}
+
+	user, err := testData.userSuccessHandlers.Tokens.Verify(token.Token)
+	if err != nil || user.ID != 1 {
+		t.Errorf("Issued token is not valid, got %v, %v", user, err)
+	}
+}
+
+func TestLoginHandlerWrongPassword(t *testing.T) {
+	testData := setupUsers(t)
+
+	req, _ := http.NewRequest("POST", "/login", testData.userWrongPasswordPayload)
+	res := httptest.NewRecorder()
+
+	testData.userSuccessHandlers.LoginHandler(res, req)
+
+	if res.Code != 401 {
+		t.Errorf("Wrong status code, expected 401, got %d", res.Code)
+	}
+}
+
+func TestLoginHandlerFailed(t *testing.T) {
+	testData := setupUsers(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("POST", "/login", testData.userPayload)
+	res := httptest.NewRecorder()
+
+	testData.userFailedHandlers.LoginHandler(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
+
+func TestUserRoleHandler(t *testing.T) {
+	testData := setupUsers(t)
+
+	req, _ := http.NewRequest("PUT", "/user/john/role", testData.rolePayload)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/user/{username}/role", testData.userSuccessHandlers.UserRoleHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 200 {
+		t.Errorf("Wrong status code, expected 200, got %d", res.Code)
+	}
+
+	var user models.User
+	json.Unmarshal(res.Body.Bytes(), &user)
+
+	if user.Username != "john" || user.Role != models.RoleViewer {
+		t.Errorf("Wrong user, expected 'john' with role 'viewer', got %v", user)
+	}
+}
+
+func TestUserRoleHandlerInvalidRole(t *testing.T) {
+	testData := setupUsers(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("PUT", "/user/john/role", testData.invalidRolePayload)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/user/{username}/role", testData.userSuccessHandlers.UserRoleHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 422 {
+		t.Errorf("Wrong status code, expected 422, got %d", res.Code)
+	}
+}
+
+func TestUserRoleHandlerNotExistingUser(t *testing.T) {
+	testData := setupUsers(t)
+
+	req, _ := http.NewRequest("PUT", "/user/jane/role", testData.rolePayload)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/user/{username}/role", testData.userSuccessHandlers.UserRoleHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 404 {
+		t.Errorf("Wrong status code, expected 404, got %d", res.Code)
+	}
+}
+
+func TestUserRoleHandlerFailed(t *testing.T) {
+	testData := setupUsers(t)
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	req, _ := http.NewRequest("PUT", "/user/john/role", testData.rolePayload)
+	res := httptest.NewRecorder()
+
+	m := mux.NewRouter()
+	m.HandleFunc("/user/{username}/role", testData.userFailedHandlers.UserRoleHandler).Methods("PUT")
+	m.ServeHTTP(res, req)
+
+	if res.Code != 500 {
+		t.Errorf("Wrong status code, expected 500, got %d", res.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package movies
+
+import (
+	"net/http"
+
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	"github.com/Mowinski/LastWatchedBackend/utils"
+	"github.com/gorilla/mux"
+)
+
+// UserHandlers join together registration, login and role handlers, users are read and written through Repository
+// and Tokens sign bearer tokens issued after login
+type UserHandlers struct {
+	Repository database.UserRepository
+	Tokens     *auth.JWT
+}
+
+// RegisterHandler create new user account
+func (uh UserHandlers) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	var payload models.UserPayload
+	err := utils.GetJSONParameters(r.Body, &payload)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	user, err := uh.Repository.CreateUser(payload.Username, passwordHash)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
+
+// LoginHandler check user credentials and return bearer token of user
+func (uh UserHandlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	var payload models.UserPayload
+	err := utils.GetJSONParameters(r.Body, &payload)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	user, err := auth.CheckCredentials(uh.Repository, payload.Username, payload.Password)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	token, expiresAt, err := uh.Tokens.Sign(user)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, models.AuthToken{Token: token, ExpiresAt: expiresAt, User: user})
+}
+
+// UserRoleHandler change role of user, it should be available only for admins
+func (uh UserHandlers) UserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	var payload models.UserRolePayload
+	err := utils.GetJSONParameters(r.Body, &payload)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	user, err := uh.Repository.UpdateUserRole(mux.Vars(r)["username"], payload.Role)
+	if err != nil {
+		utils.RespondWithError(w, r, err)
+		return
+	}
+
+	utils.RespondWithJSON(w, http.StatusOK, user)
+}
@@ -1,22 +1,122 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 )
 
-// Logger is a logger object which print log into file and stdout
-var Logger *log.Logger
+// ErrUnknownFormat is returned when log format is neither "text" nor "json"
+var ErrUnknownFormat = errors.New("Log format has to be one of: text, json")
 
-// SetLogger create new logger object and associate it with global Logger variable
+// ErrUnknownLevel is returned when log level is not one of debug, info, warn, error
+var ErrUnknownLevel = errors.New("Log level has to be one of: debug, info, warn, error")
+
+// Logger is a structured logger object which print log into file and stdout
+var Logger *slog.Logger
+
+var output *RotatingFile
+
+// Config describe where and how logs are written, empty Level means "info" and empty Format means "text".
+// Log file is rotated when it grows over MaxSize bytes or every RotateEvery, zero values disable rotation.
+type Config struct {
+	FileName    string
+	Level       string
+	Format      string
+	MaxSize     int64
+	RotateEvery time.Duration
+	MaxBackups  int
+}
+
+// SetLogger create new text logger on info level and associate it with global Logger variable
 func SetLogger(logFileName string) (err error) {
-	outputFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY, 0666)
+	return Setup(Config{FileName: logFileName})
+}
+
+// Setup create new logger object from config and associate it with global Logger variable
+func Setup(cfg Config) (err error) {
+	if output != nil {
+		output.Close()
+		output = nil
+	}
+	Logger = nil
+
+	level, err := ParseLevel(cfg.Level)
 	if err != nil {
-		Logger = nil
 		return err
 	}
-	Logger = log.New(outputFile, "", log.Lshortfile)
-	Logger.SetOutput(io.MultiWriter(os.Stdout, outputFile))
+
+	outputFile, err := OpenRotatingFile(cfg.FileName, cfg.MaxSize, cfg.RotateEvery, cfg.MaxBackups)
+	if err != nil {
+		return err
+	}
+
+	handler, err := newHandler(io.MultiWriter(os.Stdout, outputFile), cfg.Format, level)
+	if err != nil {
+		outputFile.Close()
+		return err
+	}
+
+	output = outputFile
+	Logger = slog.New(handler)
 	return nil
 }
+
+// Reopen open log file again by name, it is used after log file was moved by logrotate
+func Reopen() error {
+	if output == nil {
+		return nil
+	}
+	return output.Reopen()
+}
+
+func newHandler(output io.Writer, format string, level slog.Level) (slog.Handler, error) {
+	options := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.NewTextHandler(output, options), nil
+	case "json":
+		return slog.NewJSONHandler(output, options), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ParseLevel return slog level with selected name, empty name means info level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, ErrUnknownLevel
+}
+
+// Fatal log message on error level and stop application
+func Fatal(msg string, args ...any) {
+	Logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// WithRequestID return copy of context which carries ID of request
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext return ID of request stored by WithRequestID, or empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
@@ -1,7 +1,11 @@
 package logger
 
 import (
+	"context"
+	"encoding/json"
+	"log/slog"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -27,3 +31,74 @@ func TestLoggerCanSet(t *testing.T) {
 
 	os.Remove(TestLogFile)
 }
+
+func TestSetupJSONFormat(t *testing.T) {
+	const TestLogFile = "test_json.log"
+	defer os.Remove(TestLogFile)
+
+	err := Setup(Config{FileName: TestLogFile, Level: "warn", Format: "json"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	Logger.Info("Skipped message")
+	Logger.Warn("Logged message", "status", 404)
+
+	content, _ := os.ReadFile(TestLogFile)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Wrong number of lines, expected 1, got %d: %q", len(lines), content)
+	}
+
+	var entry map[string]interface{}
+	err = json.Unmarshal([]byte(lines[0]), &entry)
+	if err != nil {
+		t.Fatalf("Log line is not JSON, error: %s", err)
+	}
+	if entry["level"] != "WARN" || entry["msg"] != "Logged message" || entry["status"] != float64(404) {
+		t.Errorf("Wrong log entry, got %v", entry)
+	}
+}
+
+func TestSetupWrongConfig(t *testing.T) {
+	const TestLogFile = "test_wrong.log"
+	defer os.Remove(TestLogFile)
+
+	err := Setup(Config{FileName: TestLogFile, Format: "xml"})
+	if err != ErrUnknownFormat || Logger != nil {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrUnknownFormat, err)
+	}
+
+	err = Setup(Config{FileName: TestLogFile, Level: "verbose"})
+	if err != ErrUnknownLevel || Logger != nil {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrUnknownLevel, err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"":      slog.LevelInfo,
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+
+	for name, expected := range cases {
+		level, err := ParseLevel(name)
+		if err != nil || level != expected {
+			t.Errorf("ParseLevel(%q) expected %v, got %v, %v", name, expected, level, err)
+		}
+	}
+}
+
+func TestRequestIDContext(t *testing.T) {
+	if requestID := RequestIDFromContext(context.Background()); requestID != "" {
+		t.Errorf("Expected empty request ID, got %q", requestID)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc")
+	if requestID := RequestIDFromContext(ctx); requestID != "abc" {
+		t.Errorf("Wrong request ID, expected 'abc', got %q", requestID)
+	}
+}
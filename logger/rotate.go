@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// RotatingFile is log file opened in append mode which is rotated when it grows over MaxSize bytes
+// or when it is older than RotateEvery. Rotated files are named <name>.1 (newest) up to <name>.<MaxBackups>,
+// older ones are removed. Zero MaxSize or RotateEvery disable given kind of rotation.
+type RotatingFile struct {
+	Name        string
+	MaxSize     int64
+	RotateEvery time.Duration
+	MaxBackups  int
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// OpenRotatingFile open or create log file with selected rotation rules
+func OpenRotatingFile(name string, maxSize int64, rotateEvery time.Duration, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{Name: name, MaxSize: maxSize, RotateEvery: rotateEvery, MaxBackups: maxBackups, now: time.Now}
+	err := rf.open()
+	if err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// open open log file by name and only then close previous one, so logger keeps writing to old file
+// when new one can not be opened
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.Name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if rf.file != nil {
+		rf.file.Close()
+	}
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+	return nil
+}
+
+// Write append p to log file, file is rotated before write when it is too big or too old. When rotation
+// fails p is still written to the current file, failure is reported on stderr and rotation is retried
+// only after next MaxSize bytes or RotateEvery.
+func (rf *RotatingFile) Write(p []byte) (n int, err error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if rf.shouldRotate(int64(len(p))) {
+		rotateErr := rf.rotate()
+		if rotateErr != nil {
+			fmt.Fprintf(os.Stderr, "Can not rotate log file %s: %s\n", rf.Name, rotateErr)
+			rf.size = 0
+			rf.openedAt = rf.now()
+		}
+	}
+
+	n, err = rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *RotatingFile) shouldRotate(writeSize int64) bool {
+	if rf.MaxSize > 0 && rf.size > 0 && rf.size+writeSize > rf.MaxSize {
+		return true
+	}
+	return rf.RotateEvery > 0 && rf.now().Sub(rf.openedAt) >= rf.RotateEvery
+}
+
+// rotate move log file away while it is still open, old file is closed only when new one is opened.
+// Log file is first moved aside, so backups are renumbered only when it can be moved, and it is put back
+// when backups can not be renumbered.
+func (rf *RotatingFile) rotate() error {
+	if rf.MaxBackups <= 0 {
+		err := os.Remove(rf.Name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return rf.open()
+	}
+
+	rotating := rf.Name + ".rotating"
+	err := os.Rename(rf.Name, rotating)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	moved := err == nil
+
+	err = rf.shiftBackups()
+	if err == nil && moved {
+		err = os.Rename(rotating, rf.backupName(1))
+	}
+	if err != nil {
+		if moved {
+			os.Rename(rotating, rf.Name)
+		}
+		return err
+	}
+
+	return rf.open()
+}
+
+// shiftBackups remove the oldest backup and rename others to next numbers, freeing the first one
+func (rf *RotatingFile) shiftBackups() error {
+	err := os.Remove(rf.backupName(rf.MaxBackups))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := rf.MaxBackups - 1; i > 0; i-- {
+		err = os.Rename(rf.backupName(i), rf.backupName(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rf *RotatingFile) backupName(number int) string {
+	return fmt.Sprintf("%s.%d", rf.Name, number)
+}
+
+// Reopen open log file again by name and close the old one, it should be called after file was moved
+// by external tool like logrotate. When file can not be opened, logs are still written to the old one.
+func (rf *RotatingFile) Reopen() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.open()
+}
+
+// Close close log file
+func (rf *RotatingFile) Close() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.file.Close()
+}
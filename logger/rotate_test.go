@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, name string) string {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("Can not read %s, error: %s", name, err)
+	}
+	return string(content)
+}
+
+func TestRotatingFileAppend(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	os.WriteFile(name, []byte("old\n"), 0666)
+
+	rf, err := OpenRotatingFile(name, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	rf.Write([]byte("new\n"))
+	rf.Close()
+
+	if content := readFile(t, name); content != "old\nnew\n" {
+		t.Errorf("Log file was overwritten, got %q", content)
+	}
+}
+
+func TestRotatingFileBySize(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, err := OpenRotatingFile(name, 10, 0, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer rf.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		rf.Write([]byte(line))
+	}
+
+	if content := readFile(t, name); content != "fourth\n" {
+		t.Errorf("Wrong current file, got %q", content)
+	}
+	if content := readFile(t, name+".1"); content != "third\n" {
+		t.Errorf("Wrong first backup, got %q", content)
+	}
+	if content := readFile(t, name+".2"); content != "second\n" {
+		t.Errorf("Wrong second backup, got %q", content)
+	}
+	if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+		t.Error("Too many backups are kept")
+	}
+}
+
+func TestRotatingFileByTime(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	rf := &RotatingFile{Name: name, RotateEvery: time.Hour, MaxBackups: 1, now: func() time.Time { return now }}
+	if err := rf.open(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer rf.Close()
+
+	rf.Write([]byte("first\n"))
+	now = now.Add(30 * time.Minute)
+	rf.Write([]byte("second\n"))
+	now = now.Add(time.Hour)
+	rf.Write([]byte("third\n"))
+
+	if content := readFile(t, name); content != "third\n" {
+		t.Errorf("Wrong current file, got %q", content)
+	}
+	if content := readFile(t, name+".1"); content != "first\nsecond\n" {
+		t.Errorf("Wrong backup, got %q", content)
+	}
+}
+
+func TestRotatingFileWithoutBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, _ := OpenRotatingFile(name, 8, 0, 0)
+	defer rf.Close()
+
+	rf.Write([]byte("first\n"))
+	rf.Write([]byte("second\n"))
+
+	if content := readFile(t, name); content != "second\n" {
+		t.Errorf("Wrong current file, got %q", content)
+	}
+	if _, err := os.Stat(name + ".1"); !os.IsNotExist(err) {
+		t.Error("Backup is kept although MaxBackups is 0")
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, _ := OpenRotatingFile(name, 0, 0, 0)
+	defer rf.Close()
+
+	rf.Write([]byte("before\n"))
+	os.Rename(name, name+".moved")
+
+	err := rf.Reopen()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	rf.Write([]byte("after\n"))
+
+	if content := readFile(t, name); content != "after\n" {
+		t.Errorf("Wrong reopened file, got %q", content)
+	}
+	if content := readFile(t, name+".moved"); content != "before\n" {
+		t.Errorf("Wrong moved file, got %q", content)
+	}
+}
+
+func TestRotatingFileRecoversFromFailedRotation(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, _ := OpenRotatingFile(name, 10, 0, 1)
+	defer rf.Close()
+
+	rf.Write([]byte("first\n"))
+	// not empty directory can not be removed nor replaced by renamed log file
+	os.Mkdir(name+".1", 0777)
+	os.WriteFile(filepath.Join(name+".1", "blocker"), nil, 0666)
+
+	_, err := rf.Write([]byte("second\n"))
+	if err != nil {
+		t.Fatalf("Line should be written to old file when rotation fails, got error %s", err)
+	}
+	if content := readFile(t, name); content != "first\nsecond\n" {
+		t.Errorf("Wrong file after failed rotation, got %q", content)
+	}
+
+	os.RemoveAll(name + ".1")
+	_, err = rf.Write([]byte("third\n"))
+	if err != nil {
+		t.Fatalf("Logger did not recover after failed rotation, got error %s", err)
+	}
+
+	if content := readFile(t, name); content != "third\n" {
+		t.Errorf("Wrong current file, got %q", content)
+	}
+	if content := readFile(t, name+".1"); content != "first\nsecond\n" {
+		t.Errorf("Wrong backup, got %q", content)
+	}
+}
+
+func TestRotatingFileFailedRotationKeepsBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, _ := OpenRotatingFile(name, 10, 0, 3)
+	defer rf.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		rf.Write([]byte(line))
+	}
+	// not empty directory in place of the oldest backup can not be removed
+	os.Mkdir(name+".3", 0777)
+	os.WriteFile(filepath.Join(name+".3", "blocker"), nil, 0666)
+
+	rf.Write([]byte("fourth\n"))
+
+	if content := readFile(t, name); content != "third\nfourth\n" {
+		t.Errorf("Wrong current file, got %q", content)
+	}
+	if content := readFile(t, name+".1"); content != "second\n" {
+		t.Errorf("Backups were renumbered by failed rotation, first backup is %q", content)
+	}
+	if content := readFile(t, name+".2"); content != "first\n" {
+		t.Errorf("Backups were renumbered by failed rotation, second backup is %q", content)
+	}
+}
+
+func TestRotatingFileFailedReopen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "server.log")
+	rf, _ := OpenRotatingFile(name, 0, 0, 0)
+	defer rf.Close()
+
+	rf.Write([]byte("before\n"))
+	os.Rename(name, name+".moved")
+	os.Mkdir(name, 0777)
+
+	err := rf.Reopen()
+	if err == nil {
+		t.Fatal("Expected error when log file can not be opened")
+	}
+	_, err = rf.Write([]byte("kept\n"))
+	if err != nil {
+		t.Fatalf("Old log file should be kept after failed reopen, got error %s", err)
+	}
+
+	os.Remove(name)
+	err = rf.Reopen()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	rf.Write([]byte("after\n"))
+
+	if content := readFile(t, name); content != "after\n" {
+		t.Errorf("Wrong reopened file, got %q", content)
+	}
+	if content := readFile(t, name+".moved"); content != "before\nkept\n" {
+		t.Errorf("Wrong moved file, got %q", content)
+	}
+}
@@ -1,31 +1,77 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 
+	"github.com/Mowinski/LastWatchedBackend/auth"
 	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/handlers"
 	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/metrics"
+	"github.com/Mowinski/LastWatchedBackend/migrations"
 	"github.com/naoina/toml"
 )
 
 type databaseCfg struct {
+	Driver   string
 	Host     string
 	Port     int
 	User     string
 	DBName   string
 	Password string
+	Path     string
+	SSLMode  string
+
+	AutoMigrate bool
+	PingTimeout duration
+}
+
+type authCfg struct {
+	Secret        string
+	TokenLifetime duration
+	APIKeys       []auth.APIKey
+}
+
+type logCfg struct {
+	Level       string
+	Format      string
+	MaxSizeMB   int64
+	RotateEvery duration
+	MaxBackups  int
 }
 
 type config struct {
 	LogFileName string
 	Address     string
 	Port        int
+	Log         logCfg
+	Server      serverCfg
 	Database    databaseCfg
+	Auth        authCfg
+}
+
+// duration is time.Duration read from config as string, e.g. "24h" or "90m"
+type duration struct {
+	time.Duration
+}
+
+func (d *duration) UnmarshalText(text []byte) (err error) {
+	d.Duration, err = time.ParseDuration(string(text))
+	return err
 }
 
 func main() {
@@ -35,22 +81,73 @@ func main() {
 		log.Fatal("Can not read config file, check `", configFile, "` or set LASTWATCHEDMOVIE_CONFIG environment, error: ", err)
 	}
 
-	err = logger.SetLogger(cfg.LogFileName)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrateCommand(cfg.Database, os.Args[2:], os.Stdout)
+		if err != nil {
+			log.Fatal("Migration failed, error: ", err)
+		}
+		return
+	}
+
+	err = logger.Setup(logger.Config{
+		FileName:    cfg.LogFileName,
+		Level:       cfg.Log.Level,
+		Format:      cfg.Log.Format,
+		MaxSize:     cfg.Log.MaxSizeMB * 1024 * 1024,
+		RotateEvery: cfg.Log.RotateEvery.Duration,
+		MaxBackups:  cfg.Log.MaxBackups,
+	})
 	if err != nil {
 		log.Fatal("Can not open log file '", cfg.LogFileName, "', error: ", err)
 	}
+	go reopenLogOnHangup()
+
+	repository, db, err := openRepository(cfg.Database)
+	if err != nil {
+		logger.Fatal("Can not connect to database", "error", err)
+	}
 
-	dns := getDNS(cfg.Database)
-	err = database.ConnectWithDatabase(dns)
+	metricsHandler := metrics.Handler{Requests: metrics.NewRequests(metrics.DefaultBuckets), DB: db, Stats: repository}
+	healthHandler := movies.HealthHandlers{Timeout: cfg.Database.PingTimeout.Duration}
+	if db != nil {
+		healthHandler.CheckDatabase = func(ctx context.Context) error {
+			_, err := database.GetDBConnContext(ctx)
+			return err
+		}
+	}
+	router := newRouter(repository, newAuthenticator(cfg.Auth, repository), metricsHandler, healthHandler)
+
+	servers, err := newServers(cfg, router)
 	if err != nil {
-		logger.Logger.Fatal("Can not connect to database, error:", err)
+		logger.Fatal("Can not configure server", "error", err)
 	}
+	serverErr := runServers(servers, orDefault(cfg.Server.ShutdownTimeout, defaultShutdownTimeout))
 
-	addr := cfg.Address + ":" + strconv.Itoa(cfg.Port)
-	logger.Logger.Print("Server start on: ", addr)
-	router := newRouter()
+	if db != nil {
+		err = db.Close()
+		if err != nil {
+			logger.Logger.Error("Can not close database connections", "error", err)
+		}
+	}
 
-	logger.Logger.Fatal(http.ListenAndServe(addr, router))
+	if serverErr != nil {
+		logger.Fatal("Server stopped with error", "error", serverErr)
+	}
+	logger.Logger.Info("Server stopped")
+}
+
+// reopenLogOnHangup open log file again on every SIGHUP, so logrotate can move it away
+func reopenLogOnHangup() {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	for range hangup {
+		err := logger.Reopen()
+		if err != nil {
+			log.Print("Can not reopen log file, error: ", err)
+			continue
+		}
+		logger.Logger.Info("Log file reopened")
+	}
 }
 
 func prepareConfig(filename string) (cfg config, err error) {
@@ -80,7 +177,111 @@ func getConfigFileName() string {
 	return configFile
 }
 
+// newAuthenticator create authenticator from config, when secret is not set random one is used
+// and issued tokens are valid only until restart
+func newAuthenticator(authCfg authCfg, users database.UserRepository) auth.Authenticator {
+	secret := authCfg.Secret
+	if secret == "" {
+		logger.Logger.Warn("Auth secret is not set, tokens will be invalid after restart")
+		random := make([]byte, 32)
+		rand.Read(random)
+		secret = hex.EncodeToString(random)
+	}
+
+	lifetime := authCfg.TokenLifetime.Duration
+	if lifetime <= 0 {
+		lifetime = 24 * time.Hour
+	}
+
+	return auth.Authenticator{Users: users, Tokens: auth.NewJWT(secret, lifetime), APIKeys: authCfg.APIKeys}
+}
+
 func getDNS(databaseCfg databaseCfg) string {
 	return databaseCfg.User + ":" + databaseCfg.Password + "@tcp(" +
 		databaseCfg.Host + ":" + strconv.Itoa(databaseCfg.Port) + ")/" + databaseCfg.DBName + "?parseTime=true"
 }
+
+func getPostgresDNS(databaseCfg databaseCfg) string {
+	sslMode := databaseCfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		databaseCfg.Host, databaseCfg.Port, databaseCfg.User, databaseCfg.Password, databaseCfg.DBName, sslMode,
+	)
+}
+
+// openRepository return repository selected in config and its database, database is nil for memory driver
+func openRepository(databaseCfg databaseCfg) (database.Repository, *sql.DB, error) {
+	if databaseCfg.Driver == "memory" {
+		return database.NewMemoryRepository(), nil, nil
+	}
+
+	db, err := openDatabase(databaseCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var repository database.Repository
+	switch dialectName(databaseCfg) {
+	case "sqlite":
+		repository = database.NewSQLiteRepository(db)
+	case "postgres":
+		repository = database.NewPostgresRepository(db)
+	default:
+		repository = database.NewMySQLRepository(db)
+	}
+
+	if databaseCfg.AutoMigrate {
+		migrator, err := migrations.NewMigrator(db, dialectName(databaseCfg))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			logger.Logger.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return repository, db, nil
+}
+
+func openDatabase(databaseCfg databaseCfg) (*sql.DB, error) {
+	var err error
+	switch databaseCfg.Driver {
+	case "", "mysql":
+		err = database.ConnectWithDatabase(getDNS(databaseCfg))
+	case "sqlite":
+		err = database.ConnectWithDriver("sqlite", databaseCfg.Path)
+	case "postgres":
+		err = database.ConnectWithDriver("postgres", getPostgresDNS(databaseCfg))
+	default:
+		return nil, fmt.Errorf("unknown database driver `%s`", databaseCfg.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.GetDBConn()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows only one writer at once, pool is limited here, so also migrations use one connection
+	if databaseCfg.Driver == "sqlite" {
+		db.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+func dialectName(databaseCfg databaseCfg) string {
+	if databaseCfg.Driver == "" {
+		return "mysql"
+	}
+	return databaseCfg.Driver
+}
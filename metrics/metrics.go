@@ -0,0 +1,215 @@
+// Package metrics collect request metrics and expose them together with database and domain
+// statistics in Prometheus text format
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/logger"
+)
+
+// DefaultBuckets are upper bounds (in seconds) of request latency histogram buckets
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	route  string
+	method string
+	status int
+}
+
+type histogram struct {
+	counts []uint64 // counts[i] is number of observations in bucket i, the last one is +Inf
+	sum    float64
+	count  uint64
+}
+
+// Requests count requests and their latency per route name, it is safe for concurrent use
+type Requests struct {
+	mutex      sync.Mutex
+	buckets    []float64
+	counts     map[requestKey]uint64
+	histograms map[string]*histogram
+}
+
+// NewRequests create empty request metrics with latency histogram buckets
+func NewRequests(buckets []float64) *Requests {
+	return &Requests{buckets: buckets, counts: map[requestKey]uint64{}, histograms: map[string]*histogram{}}
+}
+
+// Observe record finished request
+func (rm *Requests) Observe(route string, method string, status int, latency time.Duration) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	rm.counts[requestKey{route, method, status}]++
+
+	h, ok := rm.histograms[route]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(rm.buckets)+1)}
+		rm.histograms[route] = h
+	}
+
+	seconds := latency.Seconds()
+	bucket := sort.SearchFloat64s(rm.buckets, seconds)
+	h.counts[bucket]++
+	h.sum += seconds
+	h.count++
+}
+
+// Write print request counters and latency histograms
+func (rm *Requests) Write(w io.Writer) {
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	keys := make([]requestKey, 0, len(rm.counts))
+	for key := range rm.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	writeHeader(w, "lastwatched_http_requests_total", "counter", "Number of handled HTTP requests.")
+	for _, key := range keys {
+		writeSample(w, "lastwatched_http_requests_total", rm.counts[key],
+			"route", key.route, "method", key.method, "status", strconv.Itoa(key.status))
+	}
+
+	routes := make([]string, 0, len(rm.histograms))
+	for route := range rm.histograms {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	writeHeader(w, "lastwatched_http_request_duration_seconds", "histogram", "Latency of HTTP requests.")
+	for _, route := range routes {
+		h := rm.histograms[route]
+		var cumulative uint64
+		for i, bound := range rm.buckets {
+			cumulative += h.counts[i]
+			writeSample(w, "lastwatched_http_request_duration_seconds_bucket", cumulative,
+				"route", route, "le", formatFloat(bound))
+		}
+		writeSample(w, "lastwatched_http_request_duration_seconds_bucket", h.count, "route", route, "le", "+Inf")
+		writeSample(w, "lastwatched_http_request_duration_seconds_sum", h.sum, "route", route)
+		writeSample(w, "lastwatched_http_request_duration_seconds_count", h.count, "route", route)
+	}
+}
+
+// Handler serve metrics in Prometheus text format. DB is used for connection pool statistics and
+// Stats for domain gauges, both are optional.
+type Handler struct {
+	Requests *Requests
+	DB       *sql.DB
+	Stats    database.StatsRepository
+}
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if h.Requests != nil {
+		h.Requests.Write(w)
+	}
+	if h.DB != nil {
+		writeDBStats(w, h.DB.Stats())
+	}
+	if h.Stats != nil {
+		h.writeDomainStats(w)
+	}
+}
+
+func writeDBStats(w io.Writer, stats sql.DBStats) {
+	gauges := []struct {
+		name  string
+		help  string
+		value int
+	}{
+		{"lastwatched_db_max_open_connections", "Maximum number of open connections to database.", stats.MaxOpenConnections},
+		{"lastwatched_db_open_connections", "Number of established connections to database.", stats.OpenConnections},
+		{"lastwatched_db_in_use_connections", "Number of connections currently in use.", stats.InUse},
+		{"lastwatched_db_idle_connections", "Number of idle connections.", stats.Idle},
+	}
+	for _, gauge := range gauges {
+		writeHeader(w, gauge.name, "gauge", gauge.help)
+		writeSample(w, gauge.name, gauge.value)
+	}
+
+	writeHeader(w, "lastwatched_db_wait_count_total", "counter", "Number of connections waited for.")
+	writeSample(w, "lastwatched_db_wait_count_total", stats.WaitCount)
+	writeHeader(w, "lastwatched_db_wait_duration_seconds_total", "counter", "Time blocked waiting for new connection.")
+	writeSample(w, "lastwatched_db_wait_duration_seconds_total", stats.WaitDuration.Seconds())
+	writeHeader(w, "lastwatched_db_closed_connections_total", "counter", "Number of connections closed due to pool limits.")
+	writeSample(w, "lastwatched_db_closed_connections_total", stats.MaxIdleClosed+stats.MaxIdleTimeClosed+stats.MaxLifetimeClosed)
+}
+
+func (h Handler) writeDomainStats(w io.Writer) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	stats, err := h.Stats.RetrieveStats(today)
+	if err != nil {
+		logger.Logger.Warn("Can not retrieve stats for metrics", "error", err)
+		return
+	}
+
+	gauges := []struct {
+		name  string
+		help  string
+		value int64
+	}{
+		{"lastwatched_users", "Number of registered users.", stats.Users},
+		{"lastwatched_shows", "Number of tracked shows of all users.", stats.Shows},
+		{"lastwatched_episodes", "Number of episodes of all shows.", stats.Episodes},
+		{"lastwatched_episodes_watched", "Number of watched episodes.", stats.WatchedEpisodes},
+		{"lastwatched_episodes_watched_today", "Number of episodes watched since midnight.", stats.EpisodesWatchedSince},
+	}
+	for _, gauge := range gauges {
+		writeHeader(w, gauge.name, "gauge", gauge.help)
+		writeSample(w, gauge.name, gauge.value)
+	}
+}
+
+func writeHeader(w io.Writer, name string, kind string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeSample print single metric line, labels are given as name, value pairs
+func writeSample(w io.Writer, name string, value interface{}, labels ...string) {
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+"=\""+escapeLabel(labels[i+1])+"\"")
+		}
+		name += "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	if f, ok := value.(float64); ok {
+		value = formatFloat(f)
+	}
+	fmt.Fprintf(w, "%s %v\n", name, value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/models"
+	_ "modernc.org/sqlite"
+)
+
+func TestRequestsWrite(t *testing.T) {
+	requests := NewRequests([]float64{0.1, 1})
+	requests.Observe("MovieList", "GET", 200, 50*time.Millisecond)
+	requests.Observe("MovieList", "GET", 200, 500*time.Millisecond)
+	requests.Observe("MovieList", "GET", 401, 2*time.Second)
+
+	var output bytes.Buffer
+	requests.Write(&output)
+
+	expected := []string{
+		`lastwatched_http_requests_total{route="MovieList",method="GET",status="200"} 2`,
+		`lastwatched_http_requests_total{route="MovieList",method="GET",status="401"} 1`,
+		`lastwatched_http_request_duration_seconds_bucket{route="MovieList",le="0.1"} 1`,
+		`lastwatched_http_request_duration_seconds_bucket{route="MovieList",le="1"} 2`,
+		`lastwatched_http_request_duration_seconds_bucket{route="MovieList",le="+Inf"} 3`,
+		`lastwatched_http_request_duration_seconds_sum{route="MovieList"} 2.55`,
+		`lastwatched_http_request_duration_seconds_count{route="MovieList"} 3`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output.String(), line+"\n") {
+			t.Errorf("Missing line %q in output:\n%s", line, output.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	repository := database.NewMemoryRepository()
+	user, _ := repository.CreateUser("john", "hash")
+	movie, _ := repository.CreateMovie(user.ID, models.MovieCreationPayload{MovieName: "Arrow", SeriesNumber: 1, EpisodesInSeries: 2})
+	repository.WatchNextEpisode(user.ID, movie.ID)
+
+	db, _ := sql.Open("sqlite", ":memory:")
+	handler := Handler{Requests: NewRequests(DefaultBuckets), DB: db, Stats: repository}
+
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	if !strings.HasPrefix(res.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Wrong content type, got %s", res.Header().Get("Content-Type"))
+	}
+
+	expected := []string{
+		"# TYPE lastwatched_shows gauge",
+		"lastwatched_shows 1",
+		"lastwatched_episodes 2",
+		"lastwatched_episodes_watched_today 1",
+		"lastwatched_db_open_connections 0",
+	}
+	for _, line := range expected {
+		if !strings.Contains(res.Body.String(), line+"\n") {
+			t.Errorf("Missing line %q in output:\n%s", line, res.Body.String())
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if escaped := escapeLabel("a\"b\\c\n"); escaped != `a\"b\\c\n` {
+		t.Errorf("Wrong escaped label, got %s", escaped)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Mowinski/LastWatchedBackend/migrations"
+)
+
+const migrateUsage = "usage: LastWatchedBackend migrate up | down [N] | status"
+
+// runMigrateCommand handle `migrate up`, `migrate down N` and `migrate status` subcommands
+func runMigrateCommand(databaseCfg databaseCfg, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if databaseCfg.Driver == "memory" {
+		return errors.New("memory database does not use migrations")
+	}
+
+	db, err := openDatabase(databaseCfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, dialectName(databaseCfg))
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(out, "Applied %04d %s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "Database is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errors.New(migrateUsage)
+			}
+		}
+
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "Reverted %04d %s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d %s\t%s\n", status.Version, status.Name, state)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
@@ -0,0 +1,252 @@
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version INTEGER NOT NULL PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  applied_at TIMESTAMP NOT NULL
+)`
+
+// ErrUnknownDialect is returned when there are no migrations for selected database dialect
+var ErrUnknownDialect = errors.New("There are no migrations for this database dialect")
+
+// Migration is one numbered schema change with SQL which apply and revert it
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describe migration and if it was applied on database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator apply and revert migrations of one dialect ("mysql", "postgres" or "sqlite") on database
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+// NewMigrator load embedded migrations for dialect and create schema_migrations table if it does not exist
+func NewMigrator(db *sql.DB, dialect string) (*Migrator, error) {
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(createMigrationsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// Load return migrations of selected dialect sorted by version
+func Load(dialect string) (migrations []Migration, err error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, ErrUnknownDialect
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileNameRegexp.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("wrong migration file name `%s`", entry.Name())
+		}
+
+		content, err := files.ReadFile(path.Join(dialect, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: `%s` and `%s`", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d `%s` needs both up and down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Status return all known migrations with information if they were applied
+func (m *Migrator) Status() (statuses []Status, err error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up apply all pending migrations in version order and return the applied ones
+func (m *Migrator) Up() (done []Migration, err error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err = m.run(migration.Up, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return done, fmt.Errorf("migration %d `%s` failed: %s", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down revert given number of the most recently applied migrations and return the reverted ones
+func (m *Migrator) Down(steps int) (done []Migration, err error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err = m.run(migration.Down, "DELETE FROM schema_migrations WHERE version = ?", migration.Version)
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d `%s` failed: %s", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func (m *Migrator) appliedVersions() (map[int]time.Time, error) {
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// run execute migration script and bookkeeping statement in one transaction.
+// MySQL commits DDL statements implicitly, so there failed migration may be applied partially.
+func (m *Migrator) run(script string, bookkeeping string, args ...interface{}) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range SplitStatements(script) {
+		_, err = tx.Exec(statement)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.Exec(m.rebind(bookkeeping), args...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) rebind(query string) string {
+	if m.dialect != "postgres" {
+		return query
+	}
+
+	parts := strings.Split(query, "?")
+	query = parts[0]
+	for i, part := range parts[1:] {
+		query += "$" + strconv.Itoa(i+1) + part
+	}
+	return query
+}
+
+// SplitStatements split SQL script on semicolons, comment lines and empty statements are skipped
+func SplitStatements(script string) (statements []string) {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";") {
+		statement = strings.TrimSpace(statement)
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
@@ -0,0 +1,171 @@
+package migrations
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupSQLite(t *testing.T) (*sql.DB, *Migrator) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening sqlite database", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	migrator, err := NewMigrator(db, "sqlite")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when creating migrator", err)
+	}
+
+	return db, migrator
+}
+
+func TestLoadAllDialects(t *testing.T) {
+	var versions []int
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		migrations, err := Load(dialect)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %s", dialect, err)
+		}
+
+		if versions != nil && len(versions) != len(migrations) {
+			t.Errorf("Dialect %s has %d migrations, expected %d", dialect, len(migrations), len(versions))
+		}
+		for i, migration := range migrations {
+			if migration.Version != i+1 {
+				t.Errorf("Wrong migration version in %s, expected %d, got %d", dialect, i+1, migration.Version)
+			}
+			if versions != nil && i < len(versions) && versions[i] != migration.Version {
+				t.Errorf("Dialect %s has different migrations than others", dialect)
+			}
+		}
+
+		if versions == nil {
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+		}
+	}
+}
+
+func TestLoadUnknownDialect(t *testing.T) {
+	_, err := Load("oracle")
+	if err != ErrUnknownDialect {
+		t.Errorf("Wrong error, expected '%s', got '%v'", ErrUnknownDialect, err)
+	}
+}
+
+func TestUpStatusDown(t *testing.T) {
+	db, migrator := setupSQLite(t)
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(applied) != len(migrator.migrations) {
+		t.Errorf("Wrong number of applied migrations, expected %d, got %d", len(migrator.migrations), len(applied))
+	}
+
+	_, err = db.Exec("INSERT INTO tv_series (name, url) VALUES ('Arrow', '')")
+	if err != nil {
+		t.Errorf("Schema was not created, error: %s", err)
+	}
+
+	applied, err = migrator.Up()
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Second up should not apply anything, got %v, %v", applied, err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for _, status := range statuses {
+		if !status.Applied || status.AppliedAt == nil {
+			t.Errorf("Migration %d is not applied", status.Version)
+		}
+	}
+
+	reverted, err := migrator.Down(len(migrator.migrations))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(reverted) != len(migrator.migrations) || reverted[0].Version != migrator.migrations[len(migrator.migrations)-1].Version {
+		t.Errorf("Migrations were reverted in wrong order, got %v", reverted)
+	}
+
+	_, err = db.Exec("SELECT id FROM tv_series")
+	if err == nil {
+		t.Error("Table tv_series still exists after down")
+	}
+
+	statuses, _ = migrator.Status()
+	if statuses[0].Applied {
+		t.Error("First migration is still applied after down")
+	}
+}
+
+func TestDownMoreThanApplied(t *testing.T) {
+	_, migrator := setupSQLite(t)
+
+	reverted, err := migrator.Down(5)
+	if err != nil || len(reverted) != 0 {
+		t.Errorf("Expected nothing reverted without error, got %v, %v", reverted, err)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	migrator := &Migrator{dialect: "postgres"}
+
+	query := migrator.rebind("DELETE FROM schema_migrations WHERE version = ? AND name = ?")
+	if query != "DELETE FROM schema_migrations WHERE version = $1 AND name = $2" {
+		t.Errorf("Wrong query, got %s", query)
+	}
+
+	migrator.dialect = "mysql"
+	query = migrator.rebind("DELETE FROM schema_migrations WHERE version = ?")
+	if query != "DELETE FROM schema_migrations WHERE version = ?" {
+		t.Errorf("Wrong query, got %s", query)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+-- create table
+CREATE TABLE a (id INTEGER);
+
+CREATE TABLE b (id INTEGER);
+`
+
+	statements := SplitStatements(script)
+	if len(statements) != 2 || statements[0] != "CREATE TABLE a (id INTEGER)" || statements[1] != "CREATE TABLE b (id INTEGER)" {
+		t.Errorf("Wrong statements, got %q", statements)
+	}
+}
+
+func TestUpOnExistingBaselineSchema(t *testing.T) {
+	db, migrator := setupSQLite(t)
+
+	// database created before migrations existed already has tables of the initial schema
+	for _, statement := range SplitStatements(migrator.migrations[0].Up) {
+		_, err := db.Exec(statement)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	_, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = db.Exec("INSERT INTO episode (season_id, number, title, air_date, watched) VALUES (1, 1, 'Pilot', '2012-10-10', 0)")
+	if err != nil {
+		t.Errorf("Episode details columns were not added, error: %s", err)
+	}
+}
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS `tv_series` (
+  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(150) NOT NULL,
+  `url` VARCHAR(500) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `name_UNIQUE` (`name` ASC))
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `season` (
+  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `serial_id` INT UNSIGNED NULL,
+  `number` INT NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_season_serial_idx` (`serial_id` ASC),
+  UNIQUE INDEX `fk_one_season_per_serial_unq` (`serial_id` ASC, `number` ASC),
+  CONSTRAINT `fk_season_serial`
+    FOREIGN KEY (`serial_id`)
+    REFERENCES `tv_series` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
+
+CREATE TABLE IF NOT EXISTS `episode` (
+  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `season_id` INT UNSIGNED NOT NULL,
+  `number` INT NULL,
+  `watched` VARCHAR(45) NULL DEFAULT 0,
+  `date` DATETIME NULL,
+  PRIMARY KEY (`id`),
+  INDEX `fk_episode_season_idx` (`season_id` ASC),
+  CONSTRAINT `fk_episode_season`
+    FOREIGN KEY (`season_id`)
+    REFERENCES `season` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
@@ -0,0 +1,8 @@
+ALTER TABLE `tv_series` DROP FOREIGN KEY `fk_tv_series_user`;
+
+ALTER TABLE `tv_series`
+  DROP INDEX `name_per_user_UNIQUE`,
+  ADD UNIQUE INDEX `name_UNIQUE` (`name` ASC),
+  DROP COLUMN `user_id`;
+
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `username` VARCHAR(150) NOT NULL,
+  `password_hash` VARCHAR(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `username_UNIQUE` (`username` ASC))
+ENGINE = InnoDB;
+
+ALTER TABLE `tv_series`
+  ADD COLUMN `user_id` INT UNSIGNED NULL AFTER `id`,
+  DROP INDEX `name_UNIQUE`,
+  ADD UNIQUE INDEX `name_per_user_UNIQUE` (`user_id` ASC, `name` ASC),
+  ADD CONSTRAINT `fk_tv_series_user`
+    FOREIGN KEY (`user_id`)
+    REFERENCES `users` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION;
@@ -0,0 +1,3 @@
+ALTER TABLE `users` ADD COLUMN `role` VARCHAR(20) NOT NULL DEFAULT 'editor';
+
+UPDATE `users` SET `role` = 'admin' ORDER BY `id` LIMIT 1;
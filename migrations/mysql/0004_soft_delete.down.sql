@@ -0,0 +1 @@
+ALTER TABLE `tv_series` DROP COLUMN `deleted_at`;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS `tag` (
+  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `serial_id` INT UNSIGNED NOT NULL,
+  `name` VARCHAR(50) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `tag_per_serial_UNIQUE` (`serial_id` ASC, `name` ASC),
+  INDEX `tag_name_idx` (`name` ASC),
+  CONSTRAINT `fk_tag_serial`
+    FOREIGN KEY (`serial_id`)
+    REFERENCES `tv_series` (`id`)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION)
+ENGINE = InnoDB;
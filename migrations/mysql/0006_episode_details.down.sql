@@ -0,0 +1,3 @@
+ALTER TABLE `episode`
+  DROP COLUMN `air_date`,
+  DROP COLUMN `title`;
@@ -0,0 +1,3 @@
+ALTER TABLE `episode`
+  ADD COLUMN `title` VARCHAR(150) NULL AFTER `number`,
+  ADD COLUMN `air_date` DATE NULL AFTER `title`;
@@ -0,0 +1,4 @@
+ALTER TABLE `tv_series`
+  DROP INDEX `name_per_user_UNIQUE`,
+  DROP COLUMN `active_name`,
+  ADD UNIQUE INDEX `name_per_user_UNIQUE` (`user_id` ASC, `name` ASC);
@@ -0,0 +1,5 @@
+-- MySQL has no partial indexes, movie in trash has NULL active_name, which does not collide in UNIQUE index
+ALTER TABLE `tv_series`
+  ADD COLUMN `active_name` VARCHAR(150) GENERATED ALWAYS AS (IF(`deleted_at` IS NULL, `name`, NULL)) VIRTUAL,
+  DROP INDEX `name_per_user_UNIQUE`,
+  ADD UNIQUE INDEX `name_per_user_UNIQUE` (`user_id` ASC, `active_name` ASC);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS episode;
+DROP TABLE IF EXISTS season;
+DROP TABLE IF EXISTS tv_series;
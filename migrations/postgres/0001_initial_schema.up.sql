@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS tv_series (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(150) NOT NULL,
+  url VARCHAR(500) NULL,
+  CONSTRAINT name_unique UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS season (
+  id SERIAL PRIMARY KEY,
+  serial_id INTEGER NULL,
+  number INTEGER NOT NULL,
+  CONSTRAINT fk_one_season_per_serial_unq UNIQUE (serial_id, number),
+  CONSTRAINT fk_season_serial
+    FOREIGN KEY (serial_id)
+    REFERENCES tv_series (id)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS fk_season_serial_idx ON season (serial_id);
+
+CREATE TABLE IF NOT EXISTS episode (
+  id SERIAL PRIMARY KEY,
+  season_id INTEGER NOT NULL,
+  number INTEGER NULL,
+  watched INTEGER NULL DEFAULT 0,
+  date TIMESTAMP NULL,
+  CONSTRAINT fk_episode_season
+    FOREIGN KEY (season_id)
+    REFERENCES season (id)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS fk_episode_season_idx ON episode (season_id);
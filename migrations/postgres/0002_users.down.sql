@@ -0,0 +1,7 @@
+ALTER TABLE tv_series DROP CONSTRAINT name_per_user_unique;
+
+ALTER TABLE tv_series ADD CONSTRAINT name_unique UNIQUE (name);
+
+ALTER TABLE tv_series DROP COLUMN user_id;
+
+DROP TABLE IF EXISTS users;
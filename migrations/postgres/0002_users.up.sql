@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS users (
+  id SERIAL PRIMARY KEY,
+  username VARCHAR(150) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  CONSTRAINT username_unique UNIQUE (username)
+);
+
+ALTER TABLE tv_series ADD COLUMN user_id INTEGER NULL
+  CONSTRAINT fk_tv_series_user
+    REFERENCES users (id)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION;
+
+ALTER TABLE tv_series DROP CONSTRAINT name_unique;
+
+ALTER TABLE tv_series ADD CONSTRAINT name_per_user_unique UNIQUE (user_id, name);
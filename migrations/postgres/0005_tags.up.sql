@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS tag (
+  id SERIAL PRIMARY KEY,
+  serial_id INTEGER NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  CONSTRAINT tag_per_serial_unique UNIQUE (serial_id, name),
+  CONSTRAINT fk_tag_serial
+    FOREIGN KEY (serial_id)
+    REFERENCES tv_series (id)
+    ON DELETE NO ACTION
+    ON UPDATE NO ACTION
+);
+
+CREATE INDEX tag_name_idx ON tag (name);
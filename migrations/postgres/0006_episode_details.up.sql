@@ -0,0 +1,3 @@
+ALTER TABLE episode
+  ADD COLUMN title VARCHAR(150) NULL,
+  ADD COLUMN air_date DATE NULL;
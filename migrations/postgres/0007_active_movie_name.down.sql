@@ -0,0 +1,3 @@
+DROP INDEX name_per_user_active_unique;
+
+ALTER TABLE tv_series ADD CONSTRAINT name_per_user_unique UNIQUE (user_id, name);
@@ -0,0 +1,3 @@
+ALTER TABLE tv_series DROP CONSTRAINT name_per_user_unique;
+
+CREATE UNIQUE INDEX name_per_user_active_unique ON tv_series (user_id, name) WHERE deleted_at IS NULL;
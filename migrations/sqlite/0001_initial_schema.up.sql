@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS tv_series (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(150) NOT NULL UNIQUE,
+  url VARCHAR(500) NULL
+);
+
+CREATE TABLE IF NOT EXISTS season (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  serial_id INTEGER NULL REFERENCES tv_series (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  number INTEGER NOT NULL,
+  UNIQUE (serial_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS fk_season_serial_idx ON season (serial_id);
+
+CREATE TABLE IF NOT EXISTS episode (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  season_id INTEGER NOT NULL REFERENCES season (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  number INTEGER NULL,
+  watched INTEGER NULL DEFAULT 0,
+  date DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS fk_episode_season_idx ON episode (season_id);
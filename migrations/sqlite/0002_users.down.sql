@@ -0,0 +1,13 @@
+CREATE TABLE tv_series_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name VARCHAR(150) NOT NULL UNIQUE,
+  url VARCHAR(500) NULL
+);
+
+INSERT INTO tv_series_old (id, name, url) SELECT id, name, url FROM tv_series;
+
+DROP TABLE tv_series;
+
+ALTER TABLE tv_series_old RENAME TO tv_series;
+
+DROP TABLE IF EXISTS users;
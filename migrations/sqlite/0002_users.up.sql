@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  username VARCHAR(150) NOT NULL UNIQUE,
+  password_hash VARCHAR(255) NOT NULL
+);
+
+-- SQLite can not drop UNIQUE constraint, so tv_series is rebuilt with name unique per user
+CREATE TABLE tv_series_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NULL REFERENCES users (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  name VARCHAR(150) NOT NULL,
+  url VARCHAR(500) NULL,
+  UNIQUE (user_id, name)
+);
+
+INSERT INTO tv_series_new (id, name, url) SELECT id, name, url FROM tv_series;
+
+DROP TABLE tv_series;
+
+ALTER TABLE tv_series_new RENAME TO tv_series;
+
+CREATE INDEX IF NOT EXISTS fk_tv_series_user_idx ON tv_series (user_id);
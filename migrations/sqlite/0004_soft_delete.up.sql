@@ -0,0 +1 @@
+ALTER TABLE tv_series ADD COLUMN deleted_at DATETIME NULL;
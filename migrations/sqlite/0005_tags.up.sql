@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS tag (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  serial_id INTEGER NOT NULL REFERENCES tv_series (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  name VARCHAR(50) NOT NULL,
+  UNIQUE (serial_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS tag_name_idx ON tag (name);
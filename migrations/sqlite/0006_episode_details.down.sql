@@ -0,0 +1,2 @@
+ALTER TABLE episode DROP COLUMN air_date;
+ALTER TABLE episode DROP COLUMN title;
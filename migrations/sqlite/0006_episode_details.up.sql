@@ -0,0 +1,2 @@
+ALTER TABLE episode ADD COLUMN title VARCHAR(150) NULL;
+ALTER TABLE episode ADD COLUMN air_date DATE NULL;
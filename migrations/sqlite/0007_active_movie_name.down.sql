@@ -0,0 +1,16 @@
+CREATE TABLE tv_series_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NULL REFERENCES users (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  name VARCHAR(150) NOT NULL,
+  url VARCHAR(500) NULL,
+  deleted_at DATETIME NULL,
+  UNIQUE (user_id, name)
+);
+
+INSERT INTO tv_series_old (id, user_id, name, url, deleted_at) SELECT id, user_id, name, url, deleted_at FROM tv_series;
+
+DROP TABLE tv_series;
+
+ALTER TABLE tv_series_old RENAME TO tv_series;
+
+CREATE INDEX IF NOT EXISTS fk_tv_series_user_idx ON tv_series (user_id);
@@ -0,0 +1,18 @@
+-- SQLite can not drop UNIQUE constraint, so tv_series is rebuilt and name is unique only among movies not in trash
+CREATE TABLE tv_series_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NULL REFERENCES users (id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+  name VARCHAR(150) NOT NULL,
+  url VARCHAR(500) NULL,
+  deleted_at DATETIME NULL
+);
+
+INSERT INTO tv_series_new (id, user_id, name, url, deleted_at) SELECT id, user_id, name, url, deleted_at FROM tv_series;
+
+DROP TABLE tv_series;
+
+ALTER TABLE tv_series_new RENAME TO tv_series;
+
+CREATE INDEX IF NOT EXISTS fk_tv_series_user_idx ON tv_series (user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS name_per_user_unique ON tv_series (user_id, name) WHERE deleted_at IS NULL;
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout is format of Date in JSON, like 2017-11-21
+const DateLayout = "2006-01-02"
+
+// Date is calendar day without time of day, in JSON it is written as YYYY-MM-DD string
+type Date struct {
+	time.Time
+}
+
+// NewDate return date of the day of t
+func NewDate(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// MarshalJSON write date as YYYY-MM-DD string
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(DateLayout))
+}
+
+// UnmarshalJSON read date from YYYY-MM-DD string
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return fmt.Errorf("date should be string in YYYY-MM-DD format, got %s", data)
+	}
+
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return fmt.Errorf("date should be in YYYY-MM-DD format, got %q", value)
+	}
+	d.Time = parsed
+	return nil
+}
@@ -0,0 +1,13 @@
+package models
+
+// Statuses of health checks
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// HealthStatus describe result of readiness or liveness probe, Checks contains status of every checked dependency
+type HealthStatus struct {
+	Status string
+	Checks map[string]string `json:",omitempty"`
+}
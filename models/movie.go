@@ -2,17 +2,49 @@ package models
 
 import "time"
 
-// MovieItem is stuct which contains simple information about movie
+// MovieItem is stuct which contains simple information about movie, Progress is percent of watched episodes
 type MovieItem struct {
-	ID   int
-	Name string
-	URL  string
+	ID       int
+	Name     string
+	URL      string
+	Progress float64
 }
 
 // MovieItems is array type which contains list of MovieItems
 type MovieItems []MovieItem
 
-// MovieDetail descrbie details about selected movie series
+// Watch statuses of movie used to filter movie list
+const (
+	MovieStatusFinished   = "finished"    // movie has episodes and all of them are watched
+	MovieStatusInProgress = "in-progress" // some, but not all episodes are watched
+	MovieStatusNotStarted = "not-started" // no episode is watched
+)
+
+// Columns which movie list can be sorted by
+const (
+	MovieSortName        = "name"
+	MovieSortCreated     = "created"
+	MovieSortLastWatched = "lastWatched"
+	MovieSortProgress    = "progress"
+)
+
+// MovieListQuery describe which movies of user are listed and in which order, empty fields do not filter.
+// Movies are sorted by creation by default, movies without watched episode are placed at the end
+// when they are sorted by last watched episode.
+type MovieListQuery struct {
+	SearchString string // SQL LIKE pattern which movie name has to match
+	Status       string `validate:"oneof=finished in-progress not-started"`
+	HasUnwatched *bool
+	WatchedSince *time.Time // the last episode was watched not earlier than WatchedSince
+	Tag          string     `validate:"max=50"`
+	Sort         string     `validate:"oneof=name created lastWatched progress"`
+	Order        string     `validate:"oneof=asc desc"`
+	Limit        int        `validate:"min=0"`
+	Skip         int        `validate:"min=0"`
+}
+
+// MovieDetail descrbie details about selected movie series,
+// Finished is set when there is no more episode to watch after the last watched one, movie without episodes is not finished
 type MovieDetail struct {
 	ID                       int64
 	Name                     string
@@ -20,22 +52,108 @@ type MovieDetail struct {
 	SeriesCount              int
 	LastWatchedEpisode       Episode
 	DateOfLastWatchedEpisode time.Time
+	Finished                 bool
+	Tags                     []string
 }
 
-// MovieCreationPayload describe information necessary to create movie object in database
+// TrashItem is movie moved to trash, it is hidden from movie list and details until it is restored or purged
+type TrashItem struct {
+	ID        int64
+	Name      string
+	URL       string
+	DeletedAt time.Time
+}
+
+// TrashItems is array type which contains movies in trash
+type TrashItems []TrashItem
+
+// MovieDeletion describe how many rows were removed together with deleted movie
+type MovieDeletion struct {
+	Movies   int64
+	Seasons  int64
+	Episodes int64
+}
+
+// MaxEpisodesPerMovie limit number of episodes in all seasons of movie, so one request does not create too many rows
+const MaxEpisodesPerMovie = 5000
+
+// MovieCreationPayload describe information necessary to create movie object in database,
+// Seasons describe number of episodes per season, when they are empty SeriesNumber seasons
+// with EpisodesInSeries episodes each are created. Limits of seasons and episodes keep single
+// request from creating too many rows, text limits match database columns.
 type MovieCreationPayload struct {
-	MovieName        string
-	URL              string
-	SeriesNumber     int
-	EpisodesInSeries int
+	MovieName        string          `validate:"required,max=150"`
+	URL              string          `validate:"url,max=500"`
+	SeriesNumber     int             `validate:"min=0,max=100"`
+	EpisodesInSeries int             `validate:"min=0,max=500"`
+	Seasons          []SeasonPayload `validate:"max=100"`
+	Tags             []string        `validate:"max=20"`
 }
 
-// MovieUpdatePayload describe information necessary to update movie object in database
+// SeasonsLayout return seasons which should be created for movie
+func (payload MovieCreationPayload) SeasonsLayout() []SeasonPayload {
+	if len(payload.Seasons) > 0 {
+		return payload.Seasons
+	}
+
+	seasons := []SeasonPayload{}
+	for seriesNumber := 1; seriesNumber <= payload.SeriesNumber; seriesNumber++ {
+		seasons = append(seasons, SeasonPayload{Number: seriesNumber, Episodes: payload.EpisodesInSeries})
+	}
+	return seasons
+}
+
+// MovieUpdatePayload describe information necessary to update movie object in database,
+// when Seasons (or SeriesNumber with EpisodesInSeries) are given movie seasons are changed to match them
+// (appended, resized or removed), when Tags are given (also empty) they replace tags of movie
 type MovieUpdatePayload struct {
-	MovieName        string
-	URL              string
-	SeriesNumber     int
-	EpisodesInSeries int
+	MovieName        string          `validate:"required,max=150"`
+	URL              string          `validate:"url,max=500"`
+	SeriesNumber     int             `validate:"min=0,max=100"`
+	EpisodesInSeries int             `validate:"min=0,max=500"`
+	Seasons          []SeasonPayload `validate:"max=100"`
+	Tags             []string        `validate:"max=20"`
+}
+
+// SeasonsLayout return seasons which movie should have after update, Seasons take precedence over
+// SeriesNumber seasons with EpisodesInSeries episodes each, which are used only when both are given.
+// Empty layout means seasons are not changed.
+func (payload MovieUpdatePayload) SeasonsLayout() []SeasonPayload {
+	if len(payload.Seasons) > 0 {
+		return payload.Seasons
+	}
+	if payload.SeriesNumber == 0 || payload.EpisodesInSeries == 0 {
+		return nil
+	}
+	return MovieCreationPayload{SeriesNumber: payload.SeriesNumber, EpisodesInSeries: payload.EpisodesInSeries}.SeasonsLayout()
+}
+
+// HasPartialSeasonsLayout tell if only one of SeriesNumber and EpisodesInSeries is given without Seasons,
+// such layout would leave seasons without episodes
+func (payload MovieUpdatePayload) HasPartialSeasonsLayout() bool {
+	return len(payload.Seasons) == 0 && (payload.SeriesNumber > 0) != (payload.EpisodesInSeries > 0)
+}
+
+// SeasonPayload describe number of episodes in selected season,
+// optional EpisodeDetails describe episodes in order (first element is first episode)
+type SeasonPayload struct {
+	Number         int              `validate:"min=1,max=100"`
+	Episodes       int              `validate:"min=0,max=500"`
+	EpisodeDetails []EpisodePayload `validate:"max=500"`
+}
+
+// EpisodesCount return number of episodes in season, it is never lower than number of episode details
+func (season SeasonPayload) EpisodesCount() int {
+	if len(season.EpisodeDetails) > season.Episodes {
+		return len(season.EpisodeDetails)
+	}
+	return season.Episodes
+}
+
+// EpisodePayload describe optional details of one episode
+type EpisodePayload struct {
+	Title   string `validate:"max=150"`
+	AirDate *Date
 }
 
 // Episode describe one episode
@@ -44,3 +162,26 @@ type Episode struct {
 	Series        int
 	EpisodeNumber int
 }
+
+// Season describe one season of movie with its watch progress (in percent)
+type Season struct {
+	Number        int
+	EpisodesCount int
+	WatchedCount  int
+	Progress      float64
+}
+
+// Seasons is array type which contains list of Seasons
+type Seasons []Season
+
+// EpisodeState describe watch state of one episode in season
+type EpisodeState struct {
+	Number  int
+	Title   string
+	AirDate *Date
+	Watched bool
+	Date    *time.Time
+}
+
+// EpisodeStates is array type which contains list of EpisodeStates
+type EpisodeStates []EpisodeState
@@ -0,0 +1,10 @@
+package models
+
+// Stats describe amount of data kept by application, it is exposed as metrics
+type Stats struct {
+	Users                int64
+	Shows                int64
+	Episodes             int64
+	WatchedEpisodes      int64
+	EpisodesWatchedSince int64
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Roles of users, the first registered user is admin and the following ones are editors
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// User describe account of person which tracks own movies, Role is one of viewer, editor or admin,
+// password hash is never sent to client
+type User struct {
+	ID           int64
+	Username     string
+	Role         string
+	PasswordHash string `json:"-"`
+}
+
+// UserPayload describe credentials sent during registration and login
+type UserPayload struct {
+	Username string `validate:"required,max=150"`
+	Password string `validate:"required,max=1024"`
+}
+
+// UserRolePayload describe new role of user
+type UserRolePayload struct {
+	Role string `validate:"required,oneof=viewer editor admin"`
+}
+
+// AuthToken describe bearer token issued after login, it has to be sent in Authorization header
+type AuthToken struct {
+	Token     string
+	ExpiresAt time.Time
+	User      User
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
 	"time"
 
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/database"
 	"github.com/Mowinski/LastWatchedBackend/handlers"
 	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/metrics"
+	"github.com/Mowinski/LastWatchedBackend/utils"
 	"github.com/gorilla/mux"
 )
 
@@ -13,46 +20,122 @@ type route struct {
 	Name        string
 	Method      string
 	Pattern     string
+	Permission  auth.Permission
 	HandlerFunc http.HandlerFunc
 }
 
-func newRouter() *mux.Router {
-	var movieHandler movies.MovieHandlers
-	movieHandler.Utils = movieHandler // TODO fix it
+func newRouter(repository database.Repository, authenticator auth.Authenticator, metricsHandler metrics.Handler, healthHandler movies.HealthHandlers) *mux.Router {
+	movieHandler := movies.MovieHandlers{Repository: repository}
+	userHandler := movies.UserHandlers{Repository: repository, Tokens: authenticator.Tokens}
 
 	routes := []route{
-		{"MovieList", "GET", "/movies", movieHandler.MovieListHandler},
-		{"MovieDetail", "GET", "/movie/{id:[0-9]+}", movieHandler.MovieDetailsHandler},
-		{"MovieCreate", "POST", "/movie", movieHandler.MovieCreateHandler},
-		{"MovieUpdate", "PUT", "/movie/{id:[0-9]+}", movieHandler.MovieUpdateHandler},
-		{"MovieDelete", "DELETE", "/movie/{id:[0-9]+}", movieHandler.MovieDeleteHandler},
+		{"Register", "POST", "/register", auth.PermissionPublic, userHandler.RegisterHandler},
+		{"Login", "POST", "/login", auth.PermissionPublic, userHandler.LoginHandler},
+		{"Metrics", "GET", "/metrics", auth.PermissionPublic, metricsHandler.ServeHTTP},
+		{"Liveness", "GET", "/healthz", auth.PermissionPublic, healthHandler.LivenessHandler},
+		{"Readiness", "GET", "/readyz", auth.PermissionPublic, healthHandler.ReadinessHandler},
+		{"UserRole", "PUT", "/user/{username}/role", auth.PermissionAdmin, userHandler.UserRoleHandler},
+		{"MovieList", "GET", "/movies", auth.PermissionRead, movieHandler.MovieListHandler},
+		{"MovieDetail", "GET", "/movie/{id:[0-9]+}", auth.PermissionRead, movieHandler.MovieDetailsHandler},
+		{"MovieCreate", "POST", "/movie", auth.PermissionWrite, movieHandler.MovieCreateHandler},
+		{"MovieUpdate", "PUT", "/movie/{id:[0-9]+}", auth.PermissionWrite, movieHandler.MovieUpdateHandler},
+		{"MovieDelete", "DELETE", "/movie/{id:[0-9]+}", auth.PermissionAdmin, movieHandler.MovieDeleteHandler},
+		{"MovieRestore", "POST", "/movie/{id:[0-9]+}/restore", auth.PermissionWrite, movieHandler.MovieRestoreHandler},
+		{"Trash", "GET", "/trash", auth.PermissionRead, movieHandler.TrashHandler},
+		{"TrashPurge", "DELETE", "/trash", auth.PermissionAdmin, movieHandler.TrashPurgeHandler},
+		{"MovieWatchNext", "POST", "/movie/{id:[0-9]+}/next", auth.PermissionWrite, movieHandler.MovieWatchNextHandler},
+		{"MovieSeasons", "GET", "/movie/{id:[0-9]+}/seasons", auth.PermissionRead, movieHandler.MovieSeasonsHandler},
+		{"SeasonEpisodes", "GET", "/movie/{id:[0-9]+}/season/{season:[0-9]+}/episodes", auth.PermissionRead, movieHandler.SeasonEpisodesHandler},
+		{"EpisodeWatched", "PUT", "/movie/{id:[0-9]+}/season/{season:[0-9]+}/episode/{episode:[0-9]+}/watched", auth.PermissionWrite, movieHandler.EpisodeWatchedHandler},
+		{"EpisodeUnwatched", "DELETE", "/movie/{id:[0-9]+}/season/{season:[0-9]+}/episode/{episode:[0-9]+}/watched", auth.PermissionWrite, movieHandler.EpisodeUnwatchedHandler},
 	}
 
 	router := mux.NewRouter().StrictSlash(true)
 	for _, route := range routes {
-		handler := loggerHandler(route.HandlerFunc, route.Name)
-		router.
-			Methods(route.Method).
-			Path(route.Pattern).
-			Name(route.Name).
-			Handler(handler)
+		var handler http.Handler = route.HandlerFunc
+		if route.Permission != auth.PermissionPublic {
+			handler = authenticator.Middleware(auth.RequirePermission(route.Permission, handler))
+		}
+		addRoute(router, route, handler, metricsHandler.Requests)
 	}
 
+	router.NotFoundHandler = loggerHandler(http.HandlerFunc(notFoundHandler), "NotFound", metricsHandler.Requests)
+	router.MethodNotAllowedHandler = loggerHandler(http.HandlerFunc(methodNotAllowedHandler), "MethodNotAllowed", metricsHandler.Requests)
 	return router
 }
 
-func loggerHandler(inner http.Handler, name string) http.Handler {
+var errRouteNotFound = apperror.NotFound("Resource not found")
+
+var errMethodNotAllowed = apperror.MethodNotAllowed("Method is not allowed for this resource")
+
+// notFoundHandler respond with error envelope to request of path without route
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithError(w, r, errRouteNotFound)
+}
+
+// methodNotAllowedHandler respond with error envelope to request of existing path with unsupported method
+func methodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	utils.RespondWithError(w, r, errMethodNotAllowed)
+}
+
+func addRoute(router *mux.Router, route route, handler http.Handler, requests *metrics.Requests) {
+	router.
+		Methods(route.Method).
+		Path(route.Pattern).
+		Name(route.Name).
+		Handler(loggerHandler(handler, route.Name, requests))
+}
+
+// loggerHandler write access log line of every request and record it in request metrics, request ID
+// is taken from X-Request-ID header or generated and it is available to inner handler through logger.RequestIDFromContext
+func loggerHandler(inner http.Handler, name string, requests *metrics.Requests) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		inner.ServeHTTP(w, r)
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > 128 {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		inner.ServeHTTP(recorder, r.WithContext(logger.WithRequestID(r.Context(), requestID)))
 
-		logger.Logger.Printf(
-			"%s\t%s\t%s\t%s",
-			r.Method,
-			r.RequestURI,
-			name,
-			time.Since(start),
+		latency := time.Since(start)
+		requests.Observe(name, r.Method, recorder.status, latency)
+		logger.Logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", name,
+			"status", recorder.status,
+			"size", recorder.size,
+			"latency_ms", float64(latency.Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+			"request_id", requestID,
 		)
 	})
 }
+
+// statusRecorder remember status code and size of response written by handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(body []byte) (int, error) {
+	n, err := sr.ResponseWriter.Write(body)
+	sr.size += n
+	return n, err
+}
+
+func newRequestID() string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return hex.EncodeToString(random)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/auth"
+	"github.com/Mowinski/LastWatchedBackend/database"
+	"github.com/Mowinski/LastWatchedBackend/handlers"
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/metrics"
+)
+
+func TestRouterErrorEnvelope(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	repository := database.NewMemoryRepository()
+	authenticator := auth.Authenticator{Users: repository, Tokens: auth.NewJWT("secret", time.Hour)}
+	router := newRouter(repository, authenticator, metrics.Handler{Requests: metrics.NewRequests(metrics.DefaultBuckets)}, movies.HealthHandlers{})
+
+	cases := []struct {
+		method string
+		path   string
+		status int
+		code   apperror.Code
+	}{
+		{"GET", "/not-existing", http.StatusNotFound, apperror.CodeNotFound},
+		{"PATCH", "/movies", http.StatusMethodNotAllowed, apperror.CodeMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.path, nil)
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		var response apperror.Response
+		err := json.Unmarshal(res.Body.Bytes(), &response)
+		if res.Code != c.status || err != nil || response.Code != c.code || response.RequestID == "" {
+			t.Errorf("Wrong response to %s %s, got %d %s", c.method, c.path, res.Code, res.Body.String())
+		}
+	}
+}
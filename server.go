@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+	"github.com/Mowinski/LastWatchedBackend/tlsutil"
+)
+
+// ErrIncompleteTLSConfig is returned when only one of TLS certificate and key files is set
+var ErrIncompleteTLSConfig = errors.New("Both tls_cert_file and tls_key_file have to be set")
+
+// ErrRedirectWithoutHTTPSPort is returned when HTTP redirect is enabled but HTTPS does not have own port
+var ErrRedirectWithoutHTTPSPort = errors.New("redirect_http requires https_port different from port")
+
+// serverCfg limits time of reading requests, writing responses, keeping idle connections
+// and waiting for in-flight requests on shutdown. When TLS files are set HTTPS is served on HTTPSPort
+// (or on main port when HTTPSPort is 0) and main port can redirect plain HTTP requests to HTTPS.
+type serverCfg struct {
+	ReadTimeout       duration
+	ReadHeaderTimeout duration
+	WriteTimeout      duration
+	IdleTimeout       duration
+	ShutdownTimeout   duration
+
+	TLSCertFile  string
+	TLSKeyFile   string
+	HTTPSPort    int
+	RedirectHTTP bool
+}
+
+// Defaults used when timeout is not set in config
+const (
+	defaultReadTimeout       = 15 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 2 * time.Minute
+	defaultShutdownTimeout   = 30 * time.Second
+)
+
+func orDefault(d duration, defaultValue time.Duration) time.Duration {
+	if d.Duration <= 0 {
+		return defaultValue
+	}
+	return d.Duration
+}
+
+func newHTTPServer(addr string, handler http.Handler, serverCfg serverCfg) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       orDefault(serverCfg.ReadTimeout, defaultReadTimeout),
+		ReadHeaderTimeout: orDefault(serverCfg.ReadHeaderTimeout, defaultReadHeaderTimeout),
+		WriteTimeout:      orDefault(serverCfg.WriteTimeout, defaultWriteTimeout),
+		IdleTimeout:       orDefault(serverCfg.IdleTimeout, defaultIdleTimeout),
+	}
+}
+
+// newServers return servers listening on ports selected in config, servers with TLSConfig serve HTTPS
+func newServers(cfg config, handler http.Handler) ([]*http.Server, error) {
+	addr := cfg.Address + ":" + strconv.Itoa(cfg.Port)
+	serverCfg := cfg.Server
+	if serverCfg.TLSCertFile == "" && serverCfg.TLSKeyFile == "" {
+		return []*http.Server{newHTTPServer(addr, handler, serverCfg)}, nil
+	}
+	if serverCfg.TLSCertFile == "" || serverCfg.TLSKeyFile == "" {
+		return nil, ErrIncompleteTLSConfig
+	}
+
+	separatePort := serverCfg.HTTPSPort != 0 && serverCfg.HTTPSPort != cfg.Port
+	if serverCfg.RedirectHTTP && !separatePort {
+		return nil, ErrRedirectWithoutHTTPSPort
+	}
+
+	reloader, err := tlsutil.NewCertificateReloader(serverCfg.TLSCertFile, serverCfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	httpsAddr := addr
+	if separatePort {
+		httpsAddr = cfg.Address + ":" + strconv.Itoa(serverCfg.HTTPSPort)
+	}
+	httpsServer := newHTTPServer(httpsAddr, handler, serverCfg)
+	httpsServer.TLSConfig = reloader.TLSConfig()
+
+	servers := []*http.Server{httpsServer}
+	if serverCfg.RedirectHTTP {
+		servers = append(servers, newHTTPServer(addr, tlsutil.RedirectHandler(serverCfg.HTTPSPort), serverCfg))
+	}
+	return servers, nil
+}
+
+// runServers serve requests until SIGINT or SIGTERM is received or one of servers fails, then all servers stop
+// accepting connections and wait for in-flight requests at most shutdownTimeout
+func runServers(servers []*http.Server, shutdownTimeout time.Duration) (err error) {
+	serveErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go func(server *http.Server) {
+			if server.TLSConfig != nil {
+				logger.Logger.Info("Server start", "address", server.Addr, "tls", true)
+				serveErr <- server.ListenAndServeTLS("", "")
+			} else {
+				logger.Logger.Info("Server start", "address", server.Addr, "tls", false)
+				serveErr <- server.ListenAndServe()
+			}
+		}(server)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	select {
+	case err = <-serveErr:
+	case sig := <-stop:
+		logger.Logger.Info("Shutting down server", "signal", sig.String(), "timeout", shutdownTimeout.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, server := range servers {
+		shutdownErr := server.Shutdown(ctx)
+		if err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+// Package tlsutil provide TLS certificates reloaded from disk and redirection of plain HTTP requests to HTTPS
+package tlsutil
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+)
+
+// DefaultCheckInterval is minimal time between checks whether certificate files changed
+const DefaultCheckInterval = 10 * time.Second
+
+// CertificateReloader keep certificate loaded from cert and key files, files are checked during TLS handshakes
+// (at most once per CheckInterval) and certificate is loaded again when they were modified.
+// When new files can not be loaded previous certificate is still used.
+type CertificateReloader struct {
+	CertFile      string
+	KeyFile       string
+	CheckInterval time.Duration
+
+	mutex       sync.Mutex
+	certificate *tls.Certificate
+	modTime     time.Time
+	checkedAt   time.Time
+	now         func() time.Time
+}
+
+// NewCertificateReloader load certificate from files, error is returned when they can not be loaded
+func NewCertificateReloader(certFile string, keyFile string) (*CertificateReloader, error) {
+	cr := &CertificateReloader{CertFile: certFile, KeyFile: keyFile, CheckInterval: DefaultCheckInterval, now: time.Now}
+
+	modTime, err := cr.filesModTime()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cr.load(modTime)
+	if err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func (cr *CertificateReloader) filesModTime() (modTime time.Time, err error) {
+	for _, name := range []string{cr.CertFile, cr.KeyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTime, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
+
+func (cr *CertificateReloader) load(modTime time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(cr.CertFile, cr.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	cr.certificate = &certificate
+	cr.modTime = modTime
+	cr.checkedAt = cr.now()
+	return nil
+}
+
+// GetCertificate return current certificate, it is used as tls.Config.GetCertificate
+func (cr *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	if cr.now().Sub(cr.checkedAt) < cr.CheckInterval {
+		return cr.certificate, nil
+	}
+	cr.checkedAt = cr.now()
+
+	modTime, err := cr.filesModTime()
+	if err != nil || modTime.Equal(cr.modTime) {
+		return cr.certificate, nil
+	}
+
+	err = cr.load(modTime)
+	if err != nil {
+		logger.Logger.Warn("Can not reload TLS certificate, previous one is used", "error", err)
+		return cr.certificate, nil
+	}
+	logger.Logger.Info("TLS certificate reloaded", "cert_file", cr.CertFile)
+	return cr.certificate, nil
+}
+
+// TLSConfig return server TLS config which uses reloaded certificate
+func (cr *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: cr.GetCertificate}
+}
+
+// RedirectHandler redirect every request to the same host and path on HTTPS port
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = strings.TrimSuffix(strings.TrimPrefix(r.Host, "["), "]")
+		}
+		// JoinHostPort keeps brackets of IPv6 address, default port is dropped afterwards
+		host = strings.TrimSuffix(net.JoinHostPort(host, strconv.Itoa(httpsPort)), ":443")
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
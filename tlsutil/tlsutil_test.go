@@ -0,0 +1,128 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Mowinski/LastWatchedBackend/logger"
+)
+
+// writeCertificate write self-signed certificate for commonName into cert and key files
+func writeCertificate(t *testing.T, certFile string, keyFile string, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Can not generate key, error: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Can not create certificate, error: %s", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+}
+
+func commonName(t *testing.T, cr *CertificateReloader) string {
+	certificate, _ := cr.GetCertificate(nil)
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatalf("Can not parse certificate, error: %s", err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertificateReloader(t *testing.T) {
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, "first")
+
+	cr, err := NewCertificateReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	now := time.Now()
+	cr.now = func() time.Time { return now }
+
+	if name := commonName(t, cr); name != "first" {
+		t.Errorf("Wrong certificate, expected 'first', got %s", name)
+	}
+
+	writeCertificate(t, certFile, keyFile, "second")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, future, future)
+
+	if name := commonName(t, cr); name != "first" {
+		t.Errorf("Certificate reloaded before check interval, got %s", name)
+	}
+
+	now = now.Add(DefaultCheckInterval)
+	if name := commonName(t, cr); name != "second" {
+		t.Errorf("Certificate was not reloaded, expected 'second', got %s", name)
+	}
+
+	os.WriteFile(keyFile, []byte("broken"), 0600)
+	later := future.Add(time.Minute)
+	os.Chtimes(keyFile, later, later)
+	now = now.Add(DefaultCheckInterval)
+	if name := commonName(t, cr); name != "second" {
+		t.Errorf("Previous certificate should be kept when new one is broken, got %s", name)
+	}
+}
+
+func TestCertificateReloaderMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewCertificateReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"))
+	if err == nil {
+		t.Error("Expected error when certificate files do not exist")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	cases := []struct {
+		port     int
+		host     string
+		location string
+	}{
+		{8443, "example.com:8080", "https://example.com:8443/movies?limit=5"},
+		{443, "example.com:80", "https://example.com/movies?limit=5"},
+		{443, "example.com", "https://example.com/movies?limit=5"},
+		{443, "[::1]:80", "https://[::1]/movies?limit=5"},
+		{443, "[::1]", "https://[::1]/movies?limit=5"},
+		{8443, "[::1]", "https://[::1]:8443/movies?limit=5"},
+	}
+
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", "/movies?limit=5", nil)
+		req.Host = c.host
+		res := httptest.NewRecorder()
+
+		RedirectHandler(c.port).ServeHTTP(res, req)
+
+		if res.Code != http.StatusMovedPermanently || res.Header().Get("Location") != c.location {
+			t.Errorf("Wrong redirect, expected 301 to %s, got %d to %s", c.location, res.Code, res.Header().Get("Location"))
+		}
+	}
+}
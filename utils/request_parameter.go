@@ -4,6 +4,10 @@ import (
 	"encoding/json"
 	"io"
 	"strconv"
+	"strings"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+	"github.com/Mowinski/LastWatchedBackend/validation"
 )
 
 // GetIntOrDefault return value as string or if value is empty or not string return defaultValue
@@ -18,10 +22,21 @@ func GetIntOrDefault(value string, defaultValue int) int {
 	return ret
 }
 
-// GetJSONParameters ...
+// ErrInvalidJSON is returned when request body is not valid JSON, details contain decoder error
+var ErrInvalidJSON = apperror.BadRequest("Request body is not valid JSON")
+
+// GetJSONParameters decode JSON request body into out and validate it with rules from `validate` struct tags,
+// unknown fields are rejected
 func GetJSONParameters(body io.ReadCloser, out interface{}) error {
 	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
 	err := decoder.Decode(&out)
 	defer body.Close()
-	return err
+	if err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return validation.Fields(validation.FieldError{Field: strings.Trim(field, `"`), Message: "is unknown field"})
+		}
+		return ErrInvalidJSON.WithDetails(err.Error())
+	}
+	return validation.Struct(out)
 }
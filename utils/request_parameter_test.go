@@ -2,6 +2,8 @@ package utils
 
 import (
 	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/models"
 )
 
 type JSONMock struct {
@@ -44,3 +46,20 @@ func TestGetJSONParameters(t *testing.T) {
 		t.Errorf("Wrong return value, expected 'test', got %s", out.Name)
 	}
 }
+
+func TestGetJSONParametersDate(t *testing.T) {
+	var body mockedBody = `{"title": "Pilot", "airDate": "2017-11-21"}`
+	var out models.EpisodePayload
+	err := GetJSONParameters(body, &out)
+
+	if err != nil || out.AirDate == nil || out.AirDate.Format(models.DateLayout) != "2017-11-21" {
+		t.Errorf("Wrong air date, got %v, %v", out.AirDate, err)
+	}
+
+	body = `{"title": "Pilot", "airDate": "2017-11-21T00:00:00Z"}`
+	err = GetJSONParameters(body, &models.EpisodePayload{})
+
+	if err == nil {
+		t.Error("Air date with time of day should be rejected")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/Mowinski/LastWatchedBackend/apperror"
 	"github.com/Mowinski/LastWatchedBackend/logger"
 )
 
@@ -15,8 +16,21 @@ func RespondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(response)
 }
 
-// ResponseBadRequestError return response with error and bad request status
-func ResponseBadRequestError(w http.ResponseWriter, err error) {
-	logger.Logger.Print("Error sent to client, error: ", err)
-	RespondWithJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+// RespondWithError return error response with status code matching type of error. Errors which are not
+// apperror.Error are internal or unavailable, they are logged and their message is not sent to client.
+func RespondWithError(w http.ResponseWriter, r *http.Request, err error) {
+	requestID := logger.RequestIDFromContext(r.Context())
+	appErr := apperror.From(err)
+	if appErr.Code == apperror.CodeInternal || appErr.Code == apperror.CodeUnavailable {
+		logger.Logger.Error("Internal error", "error", err, "code", appErr.Code, "request_id", requestID)
+	} else {
+		logger.Logger.Warn("Error sent to client", "error", err, "code", appErr.Code, "request_id", requestID)
+	}
+
+	RespondWithJSON(w, appErr.Status(), apperror.Response{
+		Code:      appErr.Code,
+		Message:   appErr.Message,
+		Details:   appErr.Details,
+		RequestID: requestID,
+	})
 }
@@ -1,12 +1,14 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/Mowinski/LastWatchedBackend/apperror"
 	"github.com/Mowinski/LastWatchedBackend/logger"
 )
 
@@ -23,19 +25,43 @@ func TestRespondWithJSON(t *testing.T) {
 	}
 }
 
-func TestResponseBadRequestError(t *testing.T) {
+func TestRespondWithError(t *testing.T) {
 	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/movie/1", nil)
+	r = r.WithContext(logger.WithRequestID(r.Context(), "abc"))
 
 	logger.SetLogger("test_log_file.txt")
 	defer os.Remove("test_log_file.txt")
 
-	ResponseBadRequestError(w, fmt.Errorf("Test error"))
+	RespondWithError(w, r, apperror.NotFound("Movie not found"))
 
-	if w.Result().StatusCode != http.StatusBadRequest {
-		t.Errorf("Wrong status code, got %d, expected 400", w.Result().StatusCode)
+	if w.Result().StatusCode != http.StatusNotFound {
+		t.Errorf("Wrong status code, got %d, expected 404", w.Result().StatusCode)
 	}
 
-	if w.Body.String() != "{\"error\":\"Test error\"}" {
-		t.Errorf("Wrong body, expected (%s), got (%s)", "{\"error\":\"Test error\"}", w.Body.String())
+	expected := "{\"code\":\"not_found\",\"message\":\"Movie not found\",\"details\":null,\"requestId\":\"abc\"}"
+	if w.Body.String() != expected {
+		t.Errorf("Wrong body, expected (%s), got (%s)", expected, w.Body.String())
+	}
+}
+
+func TestRespondWithInternalError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/movies", nil)
+
+	logger.SetLogger("test_log_file.txt")
+	defer os.Remove("test_log_file.txt")
+
+	RespondWithError(w, r, fmt.Errorf("Error 1045: Access denied for user 'movie_user'"))
+
+	if w.Result().StatusCode != http.StatusInternalServerError {
+		t.Errorf("Wrong status code, got %d, expected 500", w.Result().StatusCode)
+	}
+
+	var response apperror.Response
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	if response.Code != apperror.CodeInternal || response.Message != apperror.InternalMessage {
+		t.Errorf("Internal error is not sanitized, got %v", response)
 	}
 }
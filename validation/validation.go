@@ -0,0 +1,158 @@
+// Package validation check payloads against rules declared in `validate` struct tags.
+//
+// Supported rules, separated by comma:
+//
+//	required      string is not empty, number is not zero, slice is not empty
+//	min=N, max=N  number is in range, string has at most N characters, slice has at most N elements
+//	url           string is empty or absolute http(s) URL
+//	oneof=a b c   string is empty or one of listed values
+//
+// Nested structs and slices of structs are validated as well.
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+)
+
+// ErrValidationFailed is returned with details listing every invalid field
+var ErrValidationFailed = apperror.ValidationFailed("Request contains invalid fields")
+
+// FieldError describe why value of field is invalid, Field is path of field e.g. Seasons[0].Number
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Struct validate fields of struct (or pointer to struct), error lists all invalid fields
+func Struct(value interface{}) error {
+	errors := validateValue(reflect.ValueOf(value), "")
+	if len(errors) > 0 {
+		return ErrValidationFailed.WithDetails(errors)
+	}
+	return nil
+}
+
+// Fields return validation error listing given invalid fields, it is used for errors found outside of struct tags
+func Fields(errors ...FieldError) error {
+	return ErrValidationFailed.WithDetails(errors)
+}
+
+func validateValue(value reflect.Value, path string) (errors []FieldError) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		structType := value.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			errors = append(errors, validateField(value.Field(i), fieldPath, field.Tag.Get("validate"))...)
+			errors = append(errors, validateValue(value.Field(i), fieldPath)...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			errors = append(errors, validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return errors
+}
+
+func validateField(value reflect.Value, path string, tag string) (errors []FieldError) {
+	if tag == "" {
+		return nil
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, argument, _ := strings.Cut(rule, "=")
+		message := checkRule(value, name, argument)
+		if message != "" {
+			errors = append(errors, FieldError{Field: path, Message: message})
+			if name == "required" {
+				break
+			}
+		}
+	}
+	return errors
+}
+
+// checkRule return message describing broken rule, or empty string when value is valid
+func checkRule(value reflect.Value, name string, argument string) string {
+	switch name {
+	case "required":
+		if value.IsZero() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+			return "is required"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseInt(argument, 10, 64)
+		if err != nil {
+			panic("validation: invalid " + name + " argument " + argument)
+		}
+		return checkRange(value, name, limit)
+	case "url":
+		text := value.String()
+		if text == "" {
+			return ""
+		}
+		parsed, err := url.Parse(text)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return "has to be absolute http or https URL"
+		}
+	case "oneof":
+		if value.String() == "" {
+			return ""
+		}
+		for _, allowed := range strings.Fields(argument) {
+			if value.String() == allowed {
+				return ""
+			}
+		}
+		return "has to be one of: " + strings.Join(strings.Fields(argument), ", ")
+	default:
+		panic("validation: unknown rule " + name)
+	}
+	return ""
+}
+
+func checkRange(value reflect.Value, name string, limit int64) string {
+	var actual int64
+	var unit string
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = value.Int()
+	case reflect.String:
+		actual = int64(utf8.RuneCountInString(value.String()))
+		unit = " characters"
+	case reflect.Slice, reflect.Array:
+		actual = int64(value.Len())
+		unit = " elements"
+	default:
+		panic("validation: " + name + " is not supported for " + value.Kind().String())
+	}
+
+	if name == "min" && actual < limit {
+		return fmt.Sprintf("has to be at least %d%s", limit, unit)
+	}
+	if name == "max" && actual > limit {
+		return fmt.Sprintf("has to be at most %d%s", limit, unit)
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Mowinski/LastWatchedBackend/apperror"
+)
+
+type testChild struct {
+	Number int `validate:"min=1,max=10"`
+}
+
+type testPayload struct {
+	Name     string   `validate:"required,max=5"`
+	URL      string   `validate:"url"`
+	Role     string   `validate:"oneof=viewer editor"`
+	Tags     []string `validate:"max=2"`
+	Children []testChild
+	Child    *testChild
+}
+
+func fieldErrors(t *testing.T, err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	appErr := apperror.From(err)
+	if appErr.Code != apperror.CodeValidationFailed {
+		t.Fatalf("Wrong error code, expected validation_failed, got %s", appErr.Code)
+	}
+	return appErr.Details.([]FieldError)
+}
+
+func TestStructValid(t *testing.T) {
+	payload := testPayload{Name: "Arrow", URL: "https://example.com/arrow", Role: "viewer", Children: []testChild{{Number: 1}}}
+
+	err := Struct(&payload)
+	if err != nil {
+		t.Errorf("Unexpected error: %v, details %v", err, fieldErrors(t, err))
+	}
+}
+
+func TestStructInvalid(t *testing.T) {
+	payload := testPayload{
+		URL:      "example.com",
+		Role:     "owner",
+		Tags:     []string{"a", "b", "c"},
+		Children: []testChild{{Number: 1}, {Number: 11}},
+		Child:    &testChild{Number: 0},
+	}
+
+	expected := []FieldError{
+		{"Name", "is required"},
+		{"URL", "has to be absolute http or https URL"},
+		{"Role", "has to be one of: viewer, editor"},
+		{"Tags", "has to be at most 2 elements"},
+		{"Children[1].Number", "has to be at most 10"},
+		{"Child.Number", "has to be at least 1"},
+	}
+
+	errors := fieldErrors(t, Struct(payload))
+	if !reflect.DeepEqual(errors, expected) {
+		t.Errorf("Wrong errors, expected %v, got %v", expected, errors)
+	}
+}
+
+func TestStructOneOfEmpty(t *testing.T) {
+	errors := fieldErrors(t, Struct(testPayload{Name: "Arrow"}))
+	if len(errors) != 0 {
+		t.Errorf("Empty value should be accepted by oneof, got %v", errors)
+	}
+}
+
+func TestStructStringLength(t *testing.T) {
+	errors := fieldErrors(t, Struct(testPayload{Name: "Żółwie", Role: "editor"}))
+	if len(errors) != 1 || errors[0].Message != "has to be at most 5 characters" {
+		t.Errorf("Wrong errors, got %v", errors)
+	}
+
+	errors = fieldErrors(t, Struct(testPayload{Name: "Żółw", Role: "editor"}))
+	if len(errors) != 0 {
+		t.Errorf("Characters should be counted instead of bytes, got %v", errors)
+	}
+}
+
+func TestFields(t *testing.T) {
+	errors := fieldErrors(t, Fields(FieldError{"Name", "is unknown field"}))
+	if len(errors) != 1 || errors[0].Field != "Name" {
+		t.Errorf("Wrong errors, got %v", errors)
+	}
+}